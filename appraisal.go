@@ -0,0 +1,79 @@
+package pogo
+
+import (
+	"sort"
+)
+
+// Appraisal narrows down the IVs found by the IV solver. Either set Best, the
+// old team leader appraisal, or set Modern with the star rating and the bar
+// shown for each stat. The zero value doesn't filter anything.
+type Appraisal struct {
+	// Best lists the stats tied for highest, in the order "a", "d", "s",
+	// such as "a" or "ds"
+	Best string `json:"best,omitempty"`
+
+	// Modern marks Stars and Bars as set
+	Modern bool `json:"modern,omitempty"`
+	// Stars is the star rating from 0 to 3
+	Stars int `json:"stars"`
+	// Bars is how many of the three bar segments are full for attack,
+	// defense and stamina, from 0 to 3. A full bar is an IV of 15.
+	Bars [3]int `json:"bars"`
+}
+
+// starMinimums is the lowest IV sum for each star rating
+var starMinimums = []int{0, 23, 30, 37}
+
+// GetStars returns the star rating the appraisal shows for the IVs
+func GetStars(ivAttack int, ivDefense int, ivStamina int) int {
+	sum := ivAttack + ivDefense + ivStamina
+	stars := 0
+	for i, min := range starMinimums {
+		if sum >= min {
+			stars = i
+		}
+	}
+	return stars
+}
+
+// GetBar returns how many bar segments the appraisal fills for an IV
+func GetBar(iv int) int {
+	return iv / 5
+}
+
+// Matches returns true if the IVs could give the appraisal
+func (ap Appraisal) Matches(ivAttack int, ivDefense int, ivStamina int) bool {
+	if ap.Best != "" && ap.Best != getBest(ivAttack, ivDefense, ivStamina) {
+		return false
+	}
+	if ap.Modern {
+		if GetStars(ivAttack, ivDefense, ivStamina) != ap.Stars {
+			return false
+		}
+		for i, iv := range []int{ivAttack, ivDefense, ivStamina} {
+			if GetBar(iv) != ap.Bars[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// getBest returns the old appraisal for the IVs: the stats tied for highest
+func getBest(ivAttack int, ivDefense int, ivStamina int) string {
+	vals := []int{ivAttack, ivDefense, ivStamina}
+	sort.Ints(vals)
+	highest := vals[2]
+
+	best := ""
+	if ivAttack == highest {
+		best += "a"
+	}
+	if ivDefense == highest {
+		best += "d"
+	}
+	if ivStamina == highest {
+		best += "s"
+	}
+	return best
+}
@@ -0,0 +1,49 @@
+package pogo
+
+import (
+	"testing"
+)
+
+func TestAppraisal(t *testing.T) {
+	tests := []struct {
+		appraisal Appraisal
+		a, d, s   int
+		matches   bool
+	}{
+		{Appraisal{}, 0, 0, 0, true},
+		{Appraisal{Best: "a"}, 15, 14, 13, true},
+		{Appraisal{Best: "ds"}, 10, 12, 12, true},
+		{Appraisal{Best: "a"}, 12, 12, 10, false},
+		{Appraisal{Modern: true, Stars: 3, Bars: [3]int{3, 2, 2}}, 15, 14, 10, true},
+		{Appraisal{Modern: true, Stars: 2, Bars: [3]int{3, 2, 2}}, 15, 14, 10, false},
+		{Appraisal{Modern: true, Stars: 0, Bars: [3]int{0, 0, 0}}, 0, 0, 0, true},
+		{Appraisal{Modern: true, Stars: 1, Bars: [3]int{1, 1, 2}}, 4, 5, 14, false},
+		{Appraisal{Modern: true, Stars: 1, Bars: [3]int{1, 1, 2}}, 5, 9, 14, true},
+		{Appraisal{Best: "s", Modern: true, Stars: 1, Bars: [3]int{1, 1, 2}}, 9, 5, 14, true},
+	}
+	for _, test := range tests {
+		if matches := test.appraisal.Matches(test.a, test.d, test.s); matches != test.matches {
+			t.Error("For", test.appraisal, test.a, test.d, test.s, "expected", test.matches, "got", matches)
+		}
+	}
+}
+
+func TestGetIVAppraisal(t *testing.T) {
+	mewtwo, err := GetPokemon("mewtwo")
+	if err != nil {
+		t.Fatal("Unable to get mewtwo:", err)
+	}
+
+	cp, hp := mewtwo.GetCP(20, 14, 13, 12), mewtwo.GetHP(20, 12)
+	all, _ := mewtwo.GetIV(cp, hp, 0, 0, "")
+	best, _ := mewtwo.GetIV(cp, hp, 0, 0, "a")
+	modern, _ := mewtwo.GetIVAppraisal(cp, hp, 0, 0, Appraisal{Modern: true, Stars: 3, Bars: [3]int{2, 2, 2}})
+	if len(all) <= len(best) || len(best) < len(modern) || len(modern) == 0 {
+		t.Fatal("Expected each appraisal to narrow the IVs, got", len(all), len(best), len(modern))
+	}
+	for _, iv := range modern {
+		if GetStars(iv.Attack, iv.Defense, iv.Stamina) != 3 || GetBar(iv.Attack) != 2 {
+			t.Error("Expected only 3 star IVs with 2 bars, got", iv)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package pogo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors
+var (
+	ERR_INVALID_LEVEL   = errors.New("Invalid level.")
+	ERR_NO_CAPTURE_RATE = errors.New("Pokemon has no capture rate.")
+)
+
+// Encounter is how a wild pokemon behaves when encountered
+type Encounter struct {
+	BaseCaptureRate           float64       `json:"baseCaptureRate"`
+	BaseFleeRate              float64       `json:"baseFleeRate"`
+	AttackProbability         float64       `json:"attackProbability"`
+	AttackTimer               float64       `json:"attackTimer"`
+	DodgeProbability          float64       `json:"dodgeProbability"`
+	DodgeDistance             float64       `json:"dodgeDistance"`
+	JumpTime                  float64       `json:"jumpTime"`
+	CameraDistance            float64       `json:"cameraDistance"`
+	CollisionRadius           float64       `json:"collisionRadius"`
+	MinPokemonActionFrequency float64       `json:"minPokemonActionFrequency"`
+	MaxPokemonActionFrequency float64       `json:"maxPokemonActionFrequency"`
+	MovementType              *MovementType `json:"movementType,omitempty"`
+	Gender                    *GenderRatio  `json:"gender,omitempty"`
+}
+
+// MovementType is how a pokemon moves during an encounter
+type MovementType struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// GenderRatio is the chance of a pokemon being male or female. Genderless
+// pokemon have no ratio.
+type GenderRatio struct {
+	MalePercent   float64 `json:"malePercent"`
+	FemalePercent float64 `json:"femalePercent"`
+}
+
+// Ball is the catch bonus of a ball
+type Ball float64
+
+// Balls
+const (
+	POKE_BALL  Ball = 1
+	GREAT_BALL Ball = 1.5
+	ULTRA_BALL Ball = 2
+)
+
+// Berry is the catch bonus of a berry
+type Berry float64
+
+// Berries
+const (
+	NO_BERRY           Berry = 1
+	RAZZ_BERRY         Berry = 1.5
+	SILVER_PINAP_BERRY Berry = 1.8
+	GOLDEN_RAZZ_BERRY  Berry = 2.5
+)
+
+// ThrowQuality is the catch bonus for where the throw lands in the circle.
+// The bonus is 2 minus the size of the circle, from 1 up to 2 for the
+// smallest excellent circle; the constants are the middle of each range.
+type ThrowQuality float64
+
+// Throw qualities
+const (
+	THROW_NORMAL    ThrowQuality = 1
+	THROW_NICE      ThrowQuality = 1.15
+	THROW_GREAT     ThrowQuality = 1.5
+	THROW_EXCELLENT ThrowQuality = 1.85
+)
+
+// Medal is the catch bonus of a type medal
+type Medal float64
+
+// Medals
+const (
+	MEDAL_NONE     Medal = 1
+	MEDAL_BRONZE   Medal = 1.1
+	MEDAL_SILVER   Medal = 1.2
+	MEDAL_GOLD     Medal = 1.3
+	MEDAL_PLATINUM Medal = 1.4
+)
+
+const CURVEBALL_BONUS = 1.7
+
+// Throw describes a single throw. Zero values count as no bonus. Medals holds
+// the medal for each of the pokemon's types; dual type pokemon use the
+// average of the two.
+type Throw struct {
+	Ball      Ball
+	Berry     Berry
+	Quality   ThrowQuality
+	Curveball bool
+	Medals    []Medal
+}
+
+// CatchRate is the chance of catching a pokemon
+type CatchRate struct {
+	PerThrow      float64 `json:"perThrow"`      // chance each throw catches the pokemon
+	Flee          float64 `json:"flee"`          // chance the pokemon flees after breaking out
+	Overall       float64 `json:"overall"`       // chance of catching the pokemon before it flees
+	ExpectedBalls float64 `json:"expectedBalls"` // average balls thrown before it is caught or flees
+}
+
+// multiplier returns the combined catch bonus of the throw
+func (t Throw) multiplier() float64 {
+	bonus := func(b float64) float64 {
+		if b <= 0 {
+			return 1
+		}
+		return b
+	}
+
+	m := bonus(float64(t.Ball)) * bonus(float64(t.Berry)) * bonus(float64(t.Quality))
+	if t.Curveball {
+		m *= CURVEBALL_BONUS
+	}
+	if len(t.Medals) > 0 {
+		medals := 0.0
+		for _, medal := range t.Medals {
+			medals += bonus(float64(medal))
+		}
+		m *= medals / float64(len(t.Medals))
+	}
+	return m
+}
+
+// GetCatchRate returns the chance of catching a wild pokemon at a level with
+// a throw, assuming every throw hits
+func (p *Pokemon) GetCatchRate(level float64, throw Throw) (*CatchRate, error) {
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	cpm := d.getMultiplier(level)
+	if cpm == 0 {
+		return nil, ERR_INVALID_LEVEL
+	}
+	if p.Encounter.BaseCaptureRate <= 0 {
+		return nil, ERR_NO_CAPTURE_RATE
+	}
+
+	base := math.Min(p.Encounter.BaseCaptureRate/(2*cpm), 1)
+	rate := &CatchRate{
+		PerThrow: 1 - math.Pow(1-base, throw.multiplier()),
+		Flee:     p.Encounter.BaseFleeRate,
+	}
+
+	// Each throw ends the encounter unless the pokemon breaks out and stays
+	end := 1 - (1-rate.PerThrow)*(1-rate.Flee)
+	rate.Overall = rate.PerThrow / end
+	rate.ExpectedBalls = 1 / end
+	return rate, nil
+}
+
+func (c *CatchRate) Print() string {
+	return fmt.Sprintf("%.1f%% per throw, %.1f%% before it flees, %.1f balls on average", c.PerThrow*100, c.Overall*100, c.ExpectedBalls)
+}
@@ -0,0 +1,42 @@
+package pogo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetCatchRate(t *testing.T) {
+	bulbasaur, err := GetPokemon("bulbasaur")
+	if err != nil {
+		t.Fatal("Unable to get bulbasaur:", err)
+	}
+
+	tests := []struct {
+		level                    float64
+		throw                    Throw
+		perThrow, overall, balls float64
+	}{
+		// 0.2 capture rate / (2 * 0.59740001 level 20 multiplier)
+		{20, Throw{}, 0.1674, 0.6678, 3.990},
+		{20, Throw{Ball: ULTRA_BALL, Berry: GOLDEN_RAZZ_BERRY, Quality: THROW_EXCELLENT, Curveball: true}, 0.9439, 0.9941, 1.053},
+		{20, Throw{Ball: POKE_BALL, Medals: []Medal{MEDAL_GOLD, MEDAL_BRONZE}}, 0.1973, 0.7109, 3.602},
+		{1, Throw{}, 1, 1, 1},
+	}
+	for _, test := range tests {
+		rate, err := bulbasaur.GetCatchRate(test.level, test.throw)
+		if err != nil {
+			t.Error("For", test.level, test.throw, "got error", err)
+			continue
+		}
+		if math.Abs(rate.PerThrow-test.perThrow) > 0.0005 || math.Abs(rate.Overall-test.overall) > 0.0005 || math.Abs(rate.ExpectedBalls-test.balls) > 0.0005 {
+			t.Error("For", test.level, test.throw, "got", rate.Print())
+		}
+	}
+
+	if _, err := bulbasaur.GetCatchRate(0.5, Throw{}); err != ERR_INVALID_LEVEL {
+		t.Error("Expected invalid level, got", err)
+	}
+	if _, err := (&Pokemon{}).GetCatchRate(20, Throw{}); err != ERR_NO_CAPTURE_RATE {
+		t.Error("Expected no capture rate, got", err)
+	}
+}
@@ -0,0 +1,67 @@
+// Command pogodiff reports what changed between two versions of the game data.
+//
+// Each version is a directory of pokemon.json, move.json and type.json files,
+// a raw GAME_MASTER json file, or "embedded" for the data bundled with pogo.
+//
+//	pogodiff [-json] old new
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/haynesherway/pogo"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as json")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: pogodiff [-json] old new")
+		fmt.Fprintln(os.Stderr, "Each version is a json directory, a GAME_MASTER file or \"embedded\".")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	oldDex, err := load(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Unable to load", flag.Arg(0)+":", err)
+		os.Exit(1)
+	}
+	newDex, err := load(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Unable to load", flag.Arg(1)+":", err)
+		os.Exit(1)
+	}
+
+	diff := pogo.Compare(oldDex, newDex)
+	if *asJSON {
+		out, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		return
+	}
+	fmt.Println(diff.Print())
+}
+
+func load(path string) (*pogo.Pokedex, error) {
+	if path == "embedded" {
+		return pogo.Load(pogo.LoadOptions{})
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return pogo.Load(pogo.LoadOptions{Dir: path})
+	}
+	return pogo.Load(pogo.LoadOptions{GameMaster: path})
+}
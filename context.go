@@ -0,0 +1,239 @@
+package pogo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EncounterContext is a way of getting a pokemon, with the levels it can be
+// and the lowest IV it can have
+type EncounterContext struct {
+	Name    string    `json:"name"`
+	Levels  []float64 `json:"levels"`
+	IVFloor int       `json:"ivFloor"`
+}
+
+// EncounterType names one of the built in encounter contexts
+type EncounterType string
+
+// Encounter types
+const (
+	CONTEXT_WILD         EncounterType = "wild"
+	CONTEXT_WILD_BOOSTED EncounterType = "wild-boosted"
+	CONTEXT_EGG          EncounterType = "egg"
+	CONTEXT_RESEARCH     EncounterType = "research"
+	CONTEXT_RAID         EncounterType = "raid"
+	CONTEXT_ROCKET       EncounterType = "rocket"
+	CONTEXT_BATTLE       EncounterType = "battle"
+	CONTEXT_TRADE        EncounterType = "trade"
+	CONTEXT_TRADE_GOOD   EncounterType = "trade-good"
+	CONTEXT_TRADE_GREAT  EncounterType = "trade-great"
+	CONTEXT_TRADE_ULTRA  EncounterType = "trade-ultra"
+	CONTEXT_TRADE_BEST   EncounterType = "trade-best"
+	CONTEXT_TRADE_LUCKY  EncounterType = "trade-lucky"
+)
+
+// encounterTypes lists every encounter type in order
+var encounterTypes = []EncounterType{
+	CONTEXT_WILD, CONTEXT_WILD_BOOSTED, CONTEXT_EGG, CONTEXT_RESEARCH, CONTEXT_RAID, CONTEXT_ROCKET, CONTEXT_BATTLE,
+	CONTEXT_TRADE, CONTEXT_TRADE_GOOD, CONTEXT_TRADE_GREAT, CONTEXT_TRADE_ULTRA, CONTEXT_TRADE_BEST, CONTEXT_TRADE_LUCKY,
+}
+
+var encounterContexts = map[EncounterType]EncounterContext{
+	CONTEXT_WILD:         {Name: "Wild", Levels: levelRange(1, 30, 1), IVFloor: 0},
+	CONTEXT_WILD_BOOSTED: {Name: "Wild (weather boosted)", Levels: levelRange(6, 35, 1), IVFloor: 4},
+	CONTEXT_EGG:          {Name: "Egg", Levels: []float64{20}, IVFloor: 10},
+	CONTEXT_RESEARCH:     {Name: "Field research", Levels: []float64{15}, IVFloor: 10},
+	CONTEXT_RAID:         {Name: "Raid", Levels: []float64{20, 25}, IVFloor: 10},
+	CONTEXT_ROCKET:       {Name: "Team Rocket shadow", Levels: []float64{8, 13}, IVFloor: 0},
+	CONTEXT_BATTLE:       {Name: "GO Battle reward", Levels: []float64{20}, IVFloor: 10},
+	CONTEXT_TRADE:        {Name: "Trade", Levels: levelRange(1, MAX_LEVEL, 0.5), IVFloor: 0},
+	CONTEXT_TRADE_GOOD:   {Name: "Trade (good friend)", Levels: levelRange(1, MAX_LEVEL, 0.5), IVFloor: 1},
+	CONTEXT_TRADE_GREAT:  {Name: "Trade (great friend)", Levels: levelRange(1, MAX_LEVEL, 0.5), IVFloor: 2},
+	CONTEXT_TRADE_ULTRA:  {Name: "Trade (ultra friend)", Levels: levelRange(1, MAX_LEVEL, 0.5), IVFloor: 3},
+	CONTEXT_TRADE_BEST:   {Name: "Trade (best friend)", Levels: levelRange(1, MAX_LEVEL, 0.5), IVFloor: 5},
+	CONTEXT_TRADE_LUCKY:  {Name: "Trade (lucky)", Levels: levelRange(1, MAX_LEVEL, 0.5), IVFloor: 12},
+}
+
+// Context returns a copy of the encounter context, or an empty context if
+// the type isn't known
+func (t EncounterType) Context() EncounterContext {
+	ctx := encounterContexts[t]
+	ctx.Levels = append([]float64{}, ctx.Levels...)
+	return ctx
+}
+
+// EncounterContexts returns a copy of every encounter context
+func EncounterContexts() []EncounterContext {
+	contexts := []EncounterContext{}
+	for _, t := range encounterTypes {
+		contexts = append(contexts, t.Context())
+	}
+	return contexts
+}
+
+func levelRange(from float64, to float64, step float64) []float64 {
+	levels := []float64{}
+	for l := from; l <= to; l += step {
+		levels = append(levels, l)
+	}
+	return levels
+}
+
+// possibleIVs returns the IVs from 15 down to the floor
+func (ctx EncounterContext) possibleIVs() []int {
+	ivs := []int{}
+	for iv := 15; iv >= ctx.IVFloor; iv-- {
+		ivs = append(ivs, iv)
+	}
+	return ivs
+}
+
+// chartLevels returns the levels shown in charts: every level when there
+// are one or two, otherwise the lowest and highest
+func (ctx EncounterContext) chartLevels() []float64 {
+	if len(ctx.Levels) <= 2 {
+		return ctx.Levels
+	}
+	return []float64{ctx.Levels[0], ctx.Levels[len(ctx.Levels)-1]}
+}
+
+// GetCPChart returns the CP of every IV spread possible in the context, at
+// its lowest and highest levels. Spreads are sorted by CP at the highest
+// level, then the lower ones. CP15, CP20 and CP25 are only filled in when
+// they are one of those levels.
+func (p *Pokemon) GetCPChart(ctx EncounterContext) ([]IVStat, string) {
+	possibleIVs := ctx.possibleIVs()
+	levels := ctx.chartLevels()
+
+	str := "[ % ]Ak|Df|St["
+	columns := []string{}
+	for _, l := range levels {
+		columns = append(columns, fmt.Sprintf(" %-3v", l))
+	}
+	str += strings.Join(columns, "|") + "]\n"
+	str += "------------------------\n"
+
+	ivs := []IVStat{}
+	cps := map[IVStat][]int{}
+	for _, a := range possibleIVs {
+		for _, d := range possibleIVs {
+			for _, s := range possibleIVs {
+				top := levels[len(levels)-1]
+				iv := IVStat{
+					Attack:  a,
+					Defense: d,
+					Stamina: s,
+					Level:   top,
+					CP:      p.GetCP(top, a, d, s),
+					Percent: round(float64((a+d+s)*100) / float64(45)),
+				}
+				column := []int{}
+				for i := len(levels) - 1; i >= 0; i-- {
+					cp := p.GetCP(levels[i], a, d, s)
+					column = append(column, cp)
+					switch levels[i] {
+					case 15:
+						iv.CP15 = cp
+					case 20:
+						iv.CP20 = cp
+					case 25:
+						iv.CP25 = cp
+					}
+				}
+				ivs = append(ivs, iv)
+				cps[iv] = column
+			}
+		}
+	}
+
+	sort.Slice(ivs, func(i, j int) bool {
+		s1, s2 := ivs[i], ivs[j]
+		cp1, cp2 := cps[s1], cps[s2]
+		for l := range cp1 {
+			if cp1[l] != cp2[l] {
+				return cp1[l] > cp2[l]
+			}
+		}
+		if s1.Percent != s2.Percent {
+			return s1.Percent > s2.Percent
+		}
+		if s1.Attack != s2.Attack {
+			return s1.Attack > s2.Attack
+		}
+		if s1.Defense != s2.Defense {
+			return s1.Defense > s2.Defense
+		}
+		return s1.Stamina > s2.Stamina
+	})
+	chart := []string{}
+	for _, iv := range ivs {
+		columns := []string{}
+		for i := len(cps[iv]) - 1; i >= 0; i-- {
+			columns = append(columns, fmt.Sprintf("%4d", cps[iv][i]))
+		}
+		chart = append(chart, fmt.Sprintf("[%3d]%d|%d|%d[%s]", iv.Percent, iv.Attack, iv.Defense, iv.Stamina, strings.Join(columns, "|")))
+	}
+
+	return ivs, str + strings.Join(chart, "\n")
+}
+
+// GetCPRange returns the lowest and highest CP possible in the context, at
+// its lowest and highest levels
+func (p *Pokemon) GetCPRange(ctx EncounterContext) string {
+	ranges := []string{}
+	for _, l := range ctx.chartLevels() {
+		min := p.GetCP(l, ctx.IVFloor, ctx.IVFloor, ctx.IVFloor)
+		max := p.GetCP(l, 15, 15, 15)
+		ranges = append(ranges, fmt.Sprintf("Level %v: %v - **%v**", l, min, max))
+	}
+	return strings.Join(ranges, "\n")
+}
+
+// GetEncounterIV returns the IVs a pokemon with a CP could have if it came
+// from any of the contexts
+func (p *Pokemon) GetEncounterIV(cp int, contexts ...EncounterContext) ([]IVStat, string) {
+	ivList := []IVStat{}
+	seen := map[IVStat]bool{}
+
+	message := "| At | Df | St | %%% | \n"
+	message += "|----|----|----|-----| \n"
+	for _, ctx := range contexts {
+		possibleIVs := ctx.possibleIVs()
+		for _, l := range ctx.Levels {
+			for _, a := range possibleIVs {
+				for _, d := range possibleIVs {
+					for _, s := range possibleIVs {
+						if p.GetCP(l, a, d, s) != cp {
+							continue
+						}
+						stat := IVStat{
+							Attack:  a,
+							Defense: d,
+							Stamina: s,
+							Level:   l,
+							Percent: round(float64((a+d+s)*100) / float64(45)),
+						}
+						if !seen[stat] {
+							seen[stat] = true
+							ivList = append(ivList, stat)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if len(ivList) == 0 {
+		return ivList, ""
+	}
+
+	ivList = SortChart(ivList)
+	chart := []string{}
+	for _, s := range ivList {
+		chart = append(chart, s.PrintRaidIVRow())
+	}
+
+	return ivList, message + strings.Join(chart, "\n")
+}
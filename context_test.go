@@ -0,0 +1,73 @@
+package pogo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncounterContexts(t *testing.T) {
+	mewtwo, err := GetPokemon("mewtwo")
+	if err != nil {
+		t.Fatal("Unable to get mewtwo:", err)
+	}
+
+	ivs, chart := mewtwo.GetCPChart(CONTEXT_TRADE_LUCKY.Context())
+	if len(ivs) != 64 || !strings.HasPrefix(chart, "[ % ]Ak|Df|St[ 1  | 50 ]") {
+		t.Error("Expected 64 lucky spreads from level 1 to 50, got", len(ivs), strings.SplitN(chart, "\n", 2)[0])
+	}
+	if ivs[0].Attack != 15 || ivs[0].CP != mewtwo.GetCP(50, 15, 15, 15) {
+		t.Error("Expected 15/15/15 first, got", ivs[0])
+	}
+
+	// Rocket spreads are sorted by CP at level 13, not the raid levels
+	ivs, _ = mewtwo.GetCPChart(CONTEXT_ROCKET.Context())
+	for i := 1; i < len(ivs); i++ {
+		if ivs[i].CP > ivs[i-1].CP {
+			t.Fatal("Expected spreads sorted by level 13 CP, got", ivs[i-1], ivs[i])
+		}
+	}
+
+	// Only the context's own levels are filled in
+	ivs, _ = mewtwo.GetCPChart(CONTEXT_RESEARCH.Context())
+	if ivs[0].CP15 != mewtwo.GetCP(15, 15, 15, 15) || ivs[0].CP20 != 0 || ivs[0].CP25 != 0 {
+		t.Error("Expected only the level 15 CP for research, got", ivs[0])
+	}
+	ivs, _ = mewtwo.GetCPChart(CONTEXT_RAID.Context())
+	if ivs[0].CP15 != 0 || ivs[0].CP20 != mewtwo.GetCP(20, 15, 15, 15) || ivs[0].CP25 != mewtwo.GetCP(25, 15, 15, 15) {
+		t.Error("Expected the level 20 and 25 CPs for raids, got", ivs[0])
+	}
+
+	// Contexts are copies
+	wild := CONTEXT_WILD.Context()
+	wild.Levels[0] = 50
+	if CONTEXT_WILD.Context().Levels[0] != 1 || EncounterContexts()[0].Levels[0] != 1 {
+		t.Error("Expected the wild context to be unchanged")
+	}
+
+	if r := mewtwo.GetCPRange(CONTEXT_EGG.Context()); r != "Level 20: 2294 - **2387**" {
+		t.Error("Expected egg range, got", r)
+	}
+
+	// A wild spread below the weather boost floor isn't found when boosted
+	cp := mewtwo.GetCP(30, 2, 15, 15)
+	if ivs, _ := mewtwo.GetEncounterIV(cp, CONTEXT_WILD.Context()); !containsIV(ivs, 2, 15, 15, 30) {
+		t.Error("Expected wild 2/15/15 at level 30, got", ivs)
+	}
+	if ivs, _ := mewtwo.GetEncounterIV(cp, CONTEXT_WILD_BOOSTED.Context()); containsIV(ivs, 2, 15, 15, 30) {
+		t.Error("Expected no boosted 2/15/15, got", ivs)
+	}
+
+	cp = mewtwo.GetCP(31.5, 13, 12, 14)
+	if ivs, _ := mewtwo.GetEncounterIV(cp, CONTEXT_TRADE_LUCKY.Context()); !containsIV(ivs, 13, 12, 14, 31.5) {
+		t.Error("Expected lucky 13/12/14 at level 31.5, got", ivs)
+	}
+}
+
+func containsIV(ivs []IVStat, a, d, s int, level float64) bool {
+	for _, iv := range ivs {
+		if iv.Attack == a && iv.Defense == d && iv.Stamina == s && iv.Level == level {
+			return true
+		}
+	}
+	return false
+}
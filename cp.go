@@ -19,6 +19,24 @@ type IVStat struct {
 	HP       int
 	Level    float64
 	Best     string
+
+	Appraisal Appraisal
+}
+
+// Pokemon can be powered up to MAX_LEVEL, and best buddies get a boost of
+// BEST_BUDDY_BONUS levels on top of that
+const (
+	MAX_LEVEL        = 50.0
+	BEST_BUDDY_BONUS = 1.0
+	MAX_BUDDY_LEVEL  = MAX_LEVEL + BEST_BUDDY_BONUS
+)
+
+// PowerUpCost is what it takes to power up a pokemon. Above level 40 XL
+// candy is needed instead of candy.
+type PowerUpCost struct {
+	Stardust int `json:"stardust"`
+	Candy    int `json:"candy"`
+	XLCandy  int `json:"xlCandy"`
 }
 
 var stardustMap = map[int][]float64{
@@ -41,111 +59,178 @@ var stardustMap = map[int][]float64{
 	7000:  {33.0, 33.5, 34.0, 34.5},
 	8000:  {35.0, 35.5, 36.0, 36.5},
 	9000:  {37.0, 37.5, 38.0, 38.5},
-	10000: {39.0, 39.5},
-}
-
-var multiplierMap = map[float64]float64{
-	1.0:  0.094,
-	1.5:  0.135137432,
-	2.0:  0.16639787,
-	2.5:  0.192650919,
-	3.0:  0.21573247,
-	3.5:  0.236572661,
-	4.0:  0.25572005,
-	4.5:  0.273530381,
-	5.0:  0.29024988,
-	5.5:  0.335445036,
-	6.0:  0.3210876,
-	6.5:  0.335445036,
-	7.0:  0.34921268,
-	7.5:  0.362457751,
-	8.0:  0.37523559,
-	8.5:  0.387592406,
-	9.0:  0.39956728,
-	9.5:  0.411193551,
-	10.0: 0.42250001,
-	10.5: 0.432926419,
-	11.0: 0.44310755,
-	11.5: 0.4530599578,
-	12.0: 0.46279839,
-	12.5: 0.472336083,
-	13.0: 0.48168495,
-	13.5: 0.4908558,
-	14.0: 0.49985844,
-	14.5: 0.508701765,
-	15.0: 0.51739395,
-	15.5: 0.525942511,
-	16.0: 0.53435433,
-	16.5: 0.542635767,
-	17.0: 0.55079269,
-	17.5: 0.558830576,
-	18.0: 0.56675452,
-	18.5: 0.574569153,
-	19.0: 0.58227891,
-	19.5: 0.589887917,
-	20.0: 0.59740001,
-	20.5: 0.604818814,
-	21.0: 0.61215729,
-	21.5: 0.619399365,
-	22.0: 0.62656713,
-	22.5: 0.633644533,
-	23.0: 0.64065295,
-	23.5: 0.647576426,
-	24.0: 0.65443563,
-	24.5: 0.661214806,
-	25.0: 0.667934,
-	25.5: 0.674577537,
-	26.0: 0.68116492,
-	26.5: 0.687680648,
-	27.0: 0.69414365,
-	27.5: 0.700538673,
-	28.0: 0.70688421,
-	28.5: 0.713164996,
-	29.0: 0.71939909,
-	29.5: 0.725571552,
-	30.0: 0.7317,
-	30.5: 0.734741009,
-	31.0: 0.73776948,
-	31.5: 0.740785574,
-	32.0: 0.74378943,
-	32.5: 0.746781211,
-	33.0: 0.74976104,
-	33.5: 0.752729087,
-	34.0: 0.75568551,
-	34.5: 0.758630378,
-	35.0: 0.76156384,
-	35.5: 0.764486065,
-	36.0: 0.76739717,
-	36.5: 0.770297266,
-	37.0: 0.7731865,
-	37.5: 0.776064962,
-	38.0: 0.77893275,
-	38.5: 0.781790055,
-	39.0: 0.78463697,
-	39.5: 0.787473578,
-	40.0: 0.79030001,
-}
-
-func getStatValue(base int, iv int, level float64) (value float64) {
-	value = (float64(base) + float64(iv))
+	10000: {39.0, 39.5, 40.0, 40.5},
+	11000: {41.0, 41.5, 42.0, 42.5},
+	12000: {43.0, 43.5, 44.0, 44.5},
+	13000: {45.0, 45.5, 46.0, 46.5},
+	14000: {47.0, 47.5, 48.0, 48.5},
+	15000: {49.0, 49.5},
+}
+
+var candyMap = map[int][]float64{
+	1:  {1.0, 1.5, 2.0, 2.5, 3.0, 3.5, 4.0, 4.5, 5.0, 5.5, 6.0, 6.5, 7.0, 7.5, 8.0, 8.5, 9.0, 9.5, 10.0, 10.5},
+	2:  {11.0, 11.5, 12.0, 12.5, 13.0, 13.5, 14.0, 14.5, 15.0, 15.5, 16.0, 16.5, 17.0, 17.5, 18.0, 18.5, 19.0, 19.5, 20.0, 20.5},
+	3:  {21.0, 21.5, 22.0, 22.5, 23.0, 23.5, 24.0, 24.5, 25.0, 25.5},
+	4:  {26.0, 26.5, 27.0, 27.5, 28.0, 28.5, 29.0, 29.5, 30.0, 30.5},
+	6:  {31.0, 31.5, 32.0, 32.5},
+	8:  {33.0, 33.5, 34.0, 34.5},
+	10: {35.0, 35.5, 36.0, 36.5},
+	12: {37.0, 37.5, 38.0, 38.5},
+	15: {39.0, 39.5},
+}
+
+var xlCandyMap = map[int][]float64{
+	10: {40.0, 40.5, 41.0, 41.5},
+	12: {42.0, 42.5, 43.0, 43.5},
+	15: {44.0, 44.5, 45.0, 45.5},
+	17: {46.0, 46.5, 47.0, 47.5},
+	20: {48.0, 48.5, 49.0, 49.5},
+}
+
+// Level is the CP multiplier for a whole level, as found in the level file
+type Level struct {
+	Level        float64 `json:"level"`
+	CPMultiplier float64 `json:"cpMultiplier"`
+}
+
+// levelMultipliers is the CP multiplier for each whole level from 1, used when
+// the game data doesn't include a level file
+var levelMultipliers = []float64{
+	0.094,      // 1
+	0.16639787, // 2
+	0.21573247, // 3
+	0.25572005, // 4
+	0.29024988, // 5
+	0.3210876,  // 6
+	0.34921268, // 7
+	0.37523559, // 8
+	0.39956728, // 9
+	0.42250001, // 10
+	0.44310755, // 11
+	0.46279839, // 12
+	0.48168495, // 13
+	0.49985844, // 14
+	0.51739395, // 15
+	0.53435433, // 16
+	0.55079269, // 17
+	0.56675452, // 18
+	0.58227891, // 19
+	0.59740001, // 20
+	0.61215729, // 21
+	0.62656713, // 22
+	0.64065295, // 23
+	0.65443563, // 24
+	0.667934,   // 25
+	0.68116492, // 26
+	0.69414365, // 27
+	0.70688421, // 28
+	0.71939909, // 29
+	0.7317,     // 30
+	0.73776948, // 31
+	0.74378943, // 32
+	0.74976104, // 33
+	0.75568551, // 34
+	0.76156384, // 35
+	0.76739717, // 36
+	0.7731865,  // 37
+	0.77893275, // 38
+	0.78463697, // 39
+	0.79030001, // 40
+	0.79530001, // 41
+	0.8003,     // 42
+	0.8053,     // 43
+	0.81029999, // 44
+	0.81529999, // 45
+	0.82029999, // 46
+	0.82529999, // 47
+	0.83029999, // 48
+	0.83529999, // 49
+	0.84029999, // 50
+	0.84529999, // 51
+}
+
+var multiplierMap = newMultiplierMap(levelMultipliers)
+
+// powerUpCost returns what it takes to power up once from a level
+func powerUpCost(level float64) (cost PowerUpCost) {
+	find := func(costMap map[int][]float64) int {
+		for c, levels := range costMap {
+			for _, l := range levels {
+				if l == level {
+					return c
+				}
+			}
+		}
+		return 0
+	}
 
+	cost.Stardust = find(stardustMap)
+	cost.Candy = find(candyMap)
+	cost.XLCandy = find(xlCandyMap)
 	return
 }
 
-func calculateCP(attack float64, defense float64, stamina float64, level float64) (cp int) {
-	if multiplier, ok := multiplierMap[level]; ok {
-		cp = int((attack * math.Pow(defense, 0.5) * math.Pow(stamina, 0.5) * math.Pow(multiplier, 2)) / 10)
+// getMultiplier returns the CP multiplier for a level, using the game data's
+// own table when it has one
+func (d *Pokedex) getMultiplier(level float64) float64 {
+	if d != nil && d.multiplierMap != nil {
+		return d.multiplierMap[level]
+	}
+	return multiplierMap[level]
+}
+
+// getLevels returns every level that has a CP multiplier
+func (d *Pokedex) getLevels() []float64 {
+	levelMap := multiplierMap
+	if d != nil && d.multiplierMap != nil {
+		levelMap = d.multiplierMap
+	}
+
+	levels := make([]float64, 0, len(levelMap))
+	for l := range levelMap {
+		levels = append(levels, l)
+	}
+	sort.Float64s(levels)
+	return levels
+}
+
+// newMultiplierMap builds a level to CP multiplier map from the whole level
+// multipliers in the game data, starting at level 1. Half levels are derived
+// from the levels either side of them.
+func newMultiplierMap(cpMultipliers []float64) map[float64]float64 {
+	levelMap := make(map[float64]float64)
+	for i, multiplier := range cpMultipliers {
+		level := float64(i + 1)
+		levelMap[level] = multiplier
+		if i+1 < len(cpMultipliers) {
+			next := cpMultipliers[i+1]
+			levelMap[level+0.5] = math.Sqrt((multiplier*multiplier + next*next) / 2)
+		}
 	}
+	return levelMap
+}
+
+// getMaxCP returns the CP of a pokemon with the stats at level 40 with
+// perfect IVs
+func (d *Pokedex) getMaxCP(stats PokemonStats) int {
+	return calculateCP(
+		float64(stats.BaseAttack+15),
+		float64(stats.BaseDefense+15),
+		float64(stats.BaseStamina+15),
+		d.getMultiplier(40.0),
+	)
+}
+
+func calculateCP(attack float64, defense float64, stamina float64, multiplier float64) (cp int) {
+	cp = int((attack * math.Pow(defense, 0.5) * math.Pow(stamina, 0.5) * math.Pow(multiplier, 2)) / 10)
 	if cp < 10 {
 		cp = 10
 	}
 	return
 }
 
-func calculateHP(stamina float64, level float64) (hp int) {
-	if multiplier, ok := multiplierMap[level]; ok {
-		hp = int(stamina * multiplier)
-	}
+func calculateHP(stamina float64, multiplier float64) (hp int) {
+	hp = int(stamina * multiplier)
 	if hp < 10 {
 		hp = 10
 	}
@@ -223,6 +308,10 @@ func (s *IVStat) PrintRaidIVRow() string {
 	return fmt.Sprintf("| %2d | %2d | %2d [ %d%% ]", s.Attack, s.Defense, s.Stamina, s.Percent)
 }
 
+func (s *IVStat) PrintLevelRow() string {
+	return fmt.Sprintf("|%4.1f| %5d | %4d |", s.Level, s.CP, s.HP)
+}
+
 func (s *IVStat) PrintIVRow() string {
 	return fmt.Sprintf("|%4.1f| %2d | %2d | %2d [%d%%]  ", s.Level, s.Attack, s.Defense, s.Stamina, s.Percent)
 }
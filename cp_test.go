@@ -0,0 +1,60 @@
+package pogo
+
+import (
+	"testing"
+)
+
+func TestPowerUpCostTotals(t *testing.T) {
+	tests := []struct {
+		from, to float64
+		cost     PowerUpCost
+	}{
+		{1, 40, PowerUpCost{Stardust: 270000, Candy: 304}},
+		{40, 50, PowerUpCost{Stardust: 250000, XLCandy: 296}},
+		{50, 51, PowerUpCost{}},
+	}
+	for _, test := range tests {
+		total := PowerUpCost{}
+		for l := test.from; l < test.to; l += 0.5 {
+			cost := powerUpCost(l)
+			total.Stardust += cost.Stardust
+			total.Candy += cost.Candy
+			total.XLCandy += cost.XLCandy
+		}
+		if total != test.cost {
+			t.Error("From", test.from, "to", test.to, "expected", test.cost, "got", total)
+		}
+	}
+}
+
+func TestHighLevels(t *testing.T) {
+	mewtwo, err := GetPokemon("mewtwo")
+	if err != nil {
+		t.Fatal("Unable to get mewtwo:", err)
+	}
+
+	tests := []struct {
+		level float64
+		cp    int
+	}{
+		{40, 4178},
+		{45, 4447},
+		{50, 4724},
+		{51, 4780},
+	}
+	for _, test := range tests {
+		if cp := mewtwo.GetCP(test.level, 15, 15, 15); cp != test.cp {
+			t.Error("For level", test.level, "expected", test.cp, "got", cp)
+		}
+	}
+
+	ivs, _ := mewtwo.GetIV(4724, mewtwo.GetHP(50, 15), 0, 0, "")
+	if len(ivs) == 0 || ivs[0].Level != 50 || ivs[0].Attack != 15 {
+		t.Error("Expected level 50 15/15/15 mewtwo, got", ivs)
+	}
+
+	chart, _ := mewtwo.GetLevelCPChart(15, 15, 15)
+	if len(chart) != 51 || chart[50].Level != 51 || chart[50].CP != 4780 {
+		t.Error("Expected chart through level 51, got", len(chart))
+	}
+}
@@ -0,0 +1,185 @@
+package pogo
+
+import (
+	"sync"
+)
+
+// cpKey is a CP and HP reading
+type cpKey struct {
+	cp int32
+	hp int32
+}
+
+// cpCandidate is a level, stored as twice the level, and IVs giving a reading
+type cpCandidate struct {
+	halfLevel uint8
+	attack    uint8
+	defense   uint8
+	stamina   uint8
+}
+
+func (c cpCandidate) level() float64 {
+	return float64(c.halfLevel) / 2
+}
+
+// MAX_CP_INDEXES is how many reverse CP indexes a Pokedex keeps. Once there
+// are more, the least recently used is dropped.
+const MAX_CP_INDEXES = 16
+
+// cpIndex maps every CP and HP reading of a species to the levels and IVs
+// that give it. The candidates are kept in one slice, grouped by reading.
+type cpIndex struct {
+	once       sync.Once
+	candidates []cpCandidate
+	readings   map[cpKey][2]int32
+	lastUsed   uint64
+}
+
+// BuildCPIndex builds reverse CP indexes for the named pokemon, so looking
+// up their IVs doesn't have to try every level and IV. Building an index
+// takes about 150ms and allocates about 8MB, and each one kept takes about
+// 1.6MB, against about 40ms to look up IVs without one. Only the
+// MAX_CP_INDEXES most recently used are kept, so only build them for the
+// species that are looked up the most.
+func (d *Pokedex) BuildCPIndex(pokemon ...string) error {
+	for _, name := range pokemon {
+		p, err := d.GetPokemon(name)
+		if err != nil {
+			return err
+		}
+		d.getCPIndex(p)
+	}
+	return nil
+}
+
+// getCPIndex returns the reverse CP index for the pokemon's base stats,
+// building it if needed. Pokemon with the same base stats share an index.
+func (d *Pokedex) getCPIndex(p *Pokemon) *cpIndex {
+	key := p.Stats
+
+	d.indexLock.Lock()
+	if d.cpIndexes == nil {
+		d.cpIndexes = make(map[PokemonStats]*cpIndex)
+	}
+	index, ok := d.cpIndexes[key]
+	if !ok {
+		if len(d.cpIndexes) >= MAX_CP_INDEXES {
+			d.evictCPIndex()
+		}
+		index = &cpIndex{}
+		d.cpIndexes[key] = index
+	}
+	d.indexUses++
+	index.lastUsed = d.indexUses
+	d.indexLock.Unlock()
+
+	index.once.Do(func() {
+		index.build(d, p)
+	})
+	return index
+}
+
+// builtCPIndex returns the reverse CP index for the pokemon's base stats if
+// BuildCPIndex was asked for it, waiting for it if it is still being built
+func (d *Pokedex) builtCPIndex(p *Pokemon) (*cpIndex, bool) {
+	d.indexLock.Lock()
+	index, ok := d.cpIndexes[p.Stats]
+	if ok {
+		d.indexUses++
+		index.lastUsed = d.indexUses
+	}
+	d.indexLock.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	index.once.Do(func() {
+		index.build(d, p)
+	})
+	return index, true
+}
+
+// evictCPIndex drops the least recently used index. indexLock must be held.
+func (d *Pokedex) evictCPIndex() {
+	var oldest PokemonStats
+	var oldestUse uint64
+	found := false
+	for key, index := range d.cpIndexes {
+		if !found || index.lastUsed < oldestUse {
+			oldest, oldestUse, found = key, index.lastUsed, true
+		}
+	}
+	delete(d.cpIndexes, oldest)
+}
+
+// getCPCandidates returns the levels and IVs that give a CP and HP, from the
+// pokemon's reverse CP index if one was built or else by trying them all
+func (d *Pokedex) getCPCandidates(p *Pokemon, cp int, hp int) []cpCandidate {
+	if index, ok := d.builtCPIndex(p); ok {
+		return index.lookup(cp, hp)
+	}
+	candidates := []cpCandidate{}
+	for _, l := range d.getLevels() {
+		for a := 0; a <= 15; a++ {
+			for df := 0; df <= 15; df++ {
+				for s := 0; s <= 15; s++ {
+					if p.GetCP(l, a, df, s) == cp && p.GetHP(l, s) == hp {
+						candidates = append(candidates, cpCandidate{halfLevel: uint8(l * 2), attack: uint8(a), defense: uint8(df), stamina: uint8(s)})
+					}
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// build fills the index in two passes: the first counts the candidates for
+// each reading, the second puts them in place
+func (index *cpIndex) build(d *Pokedex, p *Pokemon) {
+	levels := d.getLevels()
+	keys := make([]cpKey, 0, len(levels)*16*16*16)
+	counts := make(map[cpKey]int32)
+	for _, l := range levels {
+		for a := 0; a <= 15; a++ {
+			for df := 0; df <= 15; df++ {
+				for s := 0; s <= 15; s++ {
+					key := cpKey{cp: int32(p.GetCP(l, a, df, s)), hp: int32(p.GetHP(l, s))}
+					keys = append(keys, key)
+					counts[key]++
+				}
+			}
+		}
+	}
+
+	index.readings = make(map[cpKey][2]int32, len(counts))
+	start := int32(0)
+	for key, count := range counts {
+		index.readings[key] = [2]int32{start, start}
+		start += count
+	}
+
+	index.candidates = make([]cpCandidate, len(keys))
+	i := 0
+	for _, l := range levels {
+		for a := 0; a <= 15; a++ {
+			for df := 0; df <= 15; df++ {
+				for s := 0; s <= 15; s++ {
+					r := index.readings[keys[i]]
+					index.candidates[r[1]] = cpCandidate{halfLevel: uint8(l * 2), attack: uint8(a), defense: uint8(df), stamina: uint8(s)}
+					r[1]++
+					index.readings[keys[i]] = r
+					i++
+				}
+			}
+		}
+	}
+}
+
+// lookup returns the levels and IVs that give a CP and HP
+func (index *cpIndex) lookup(cp int, hp int) []cpCandidate {
+	r, ok := index.readings[cpKey{cp: int32(cp), hp: int32(hp)}]
+	if !ok {
+		return nil
+	}
+	return index.candidates[r[0]:r[1]]
+}
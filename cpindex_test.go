@@ -0,0 +1,135 @@
+package pogo
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// scanIV finds the IVs for a reading by trying every level and IV, as getIV
+// did before the reverse CP index
+func scanIV(p *Pokemon, cp int, hp int) []IVStat {
+	ivList := []IVStat{}
+	for _, l := range p.pokedex.getLevels() {
+		for a := 15; a >= 0; a-- {
+			for d := 15; d >= 0; d-- {
+				for s := 15; s >= 0; s-- {
+					if p.GetCP(l, a, d, s) == cp && p.GetHP(l, s) == hp {
+						ivList = append(ivList, IVStat{Level: l, Attack: a, Defense: d, Stamina: s, Percent: round(float64((a+d+s)*100) / float64(45))})
+					}
+				}
+			}
+		}
+	}
+	return SortChart(ivList)
+}
+
+func TestCPIndex(t *testing.T) {
+	for _, name := range []string{"pidgey", "mewtwo", "shuckle", "blissey"} {
+		p, err := GetPokemon(name)
+		if err != nil {
+			t.Fatal("Unable to get", name, err)
+		}
+		for _, l := range []float64{1, 10.5, 20, 35, 51} {
+			cp, hp := p.GetCP(l, 7, 11, 3), p.GetHP(l, 3)
+			unindexed, _ := p.GetIV(cp, hp, 0, 0, "")
+			if err := p.pokedex.BuildCPIndex(name); err != nil {
+				t.Fatal(err)
+			}
+			indexed, _ := p.GetIV(cp, hp, 0, 0, "")
+			scanned := scanIV(p, cp, hp)
+			if !reflect.DeepEqual(indexed, scanned) || !reflect.DeepEqual(unindexed, scanned) {
+				t.Error("For", name, "level", l, "indexed", len(indexed), "and unindexed", len(unindexed), "IVs but scanned", len(scanned))
+			}
+		}
+	}
+
+	// Pokemon with the same base stats share an index
+	d, _ := DefaultPokedex()
+	bulbasaur, _ := GetPokemon("bulbasaur")
+	shadow, _ := GetPokemon("bulbasaur-shadow")
+	if d.getCPIndex(bulbasaur) != d.getCPIndex(shadow) {
+		t.Error("Expected bulbasaur and shadow bulbasaur to share an index")
+	}
+	if err := d.BuildCPIndex("missingno"); err != ERR_NOT_FOUND {
+		t.Error("Expected pokemon not found, got", err)
+	}
+
+	// Only the most recently used indexes are kept
+	dex := &Pokedex{multiplierMap: map[float64]float64{1: 0.094}}
+	for i := 0; i <= MAX_CP_INDEXES; i++ {
+		dex.getCPIndex(&Pokemon{Stats: PokemonStats{BaseAttack: 100 + i, BaseDefense: 100, BaseStamina: 100}})
+		if i == 1 {
+			dex.builtCPIndex(&Pokemon{Stats: PokemonStats{BaseAttack: 100, BaseDefense: 100, BaseStamina: 100}})
+		}
+	}
+	if len(dex.cpIndexes) != MAX_CP_INDEXES {
+		t.Error("Expected", MAX_CP_INDEXES, "indexes, got", len(dex.cpIndexes))
+	}
+	if _, ok := dex.builtCPIndex(&Pokemon{Stats: PokemonStats{BaseAttack: 100, BaseDefense: 100, BaseStamina: 100}}); !ok {
+		t.Error("Expected the recently used index to be kept")
+	}
+	if _, ok := dex.builtCPIndex(&Pokemon{Stats: PokemonStats{BaseAttack: 101, BaseDefense: 100, BaseStamina: 100}}); ok {
+		t.Error("Expected the least recently used index to be dropped")
+	}
+}
+
+func TestCPIndexConcurrent(t *testing.T) {
+	d, err := NewPokedex(EmbeddedFS())
+	if err != nil {
+		t.Fatal("Unable to load pokedex:", err)
+	}
+	p, err := d.GetPokemon("mewtwo")
+	if err != nil {
+		t.Fatal("Unable to get mewtwo:", err)
+	}
+	cp, hp := p.GetCP(20, 15, 14, 13), p.GetHP(20, 13)
+
+	// Lookups while the index is being built wait for it or scan
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.BuildCPIndex("mewtwo")
+	}()
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ivs, _ := p.GetIV(cp, hp, 0, 0, ""); !containsIV(ivs, 15, 14, 13, 20) {
+				t.Error("Expected 15/14/13 at level 20, got", len(ivs), "IVs")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkGetIVScan(b *testing.B) {
+	p, _ := GetPokemon("mewtwo")
+	cp, hp := p.GetCP(20, 15, 14, 13), p.GetHP(20, 13)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanIV(p, cp, hp)
+	}
+}
+
+func BenchmarkGetIVIndexed(b *testing.B) {
+	p, _ := GetPokemon("mewtwo")
+	cp, hp := p.GetCP(20, 15, 14, 13), p.GetHP(20, 13)
+	if err := p.pokedex.BuildCPIndex("mewtwo"); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.GetIV(cp, hp, 0, 0, "")
+	}
+}
+
+func BenchmarkBuildCPIndex(b *testing.B) {
+	p, _ := GetPokemon("mewtwo")
+	d := p.pokedex
+	for i := 0; i < b.N; i++ {
+		index := &cpIndex{}
+		index.build(d, p)
+	}
+}
@@ -0,0 +1,67 @@
+package pogo
+
+import (
+	"math"
+)
+
+// STAB_BONUS is the damage multiplier for a move that shares a type with its user
+const STAB_BONUS = 1.2
+
+// GetAttack returns the attack stat a pokemon battles with at a level,
+// including the shadow bonus
+func (p *Pokemon) GetAttack(level float64, ivAttack int) float64 {
+	return p.GetStats(level, ivAttack, 0, 0, false).Attack
+}
+
+// GetDefense returns the defense stat a pokemon battles with at a level,
+// including the shadow penalty
+func (p *Pokemon) GetDefense(level float64, ivDefense int) float64 {
+	return p.GetStats(level, 0, ivDefense, 0, false).Defense
+}
+
+// GetDamage returns the damage a move used by attacker does to defender,
+// given the attack and defense stats from GetAttack and GetDefense
+func GetDamage(move *Move, attacker *Pokemon, attack float64, defender *Pokemon, defense float64) int {
+	return GetBoostedDamage(move, attacker, attack, defender, defense, 1)
+}
+
+// GetBoostedDamage returns the damage of a move with an attack boost such as
+// GetMegaBoost from a mega pokemon in the party
+func GetBoostedDamage(move *Move, attacker *Pokemon, attack float64, defender *Pokemon, defense float64, boost float64) int {
+	stab := 1.0
+	for _, t := range attacker.Types {
+		if t.ID == move.Type.ID {
+			stab = STAB_BONUS
+		}
+	}
+
+	d, _ := defender.getPokedex()
+	effectiveness := 1.0
+	for _, t := range defender.Types {
+		effectiveness *= d.getScalar(move.Type.ID, t.ID)
+	}
+
+	return int(math.Floor(0.5*move.Power*attack/defense*stab*effectiveness*boost)) + 1
+}
+
+// getScalar returns the damage scalar of one type attacking another
+func (d *Pokedex) getScalar(attackID string, defendID string) float64 {
+	if d == nil {
+		return 1
+	}
+	ty, ok := d.typeMap[attackID]
+	if !ok {
+		return 1
+	}
+	if a, ok := typeIndex[attackID]; ok {
+		if df, ok := typeIndex[defendID]; ok {
+			return d.typeMatrix[a][df]
+		}
+	}
+	for _, damage := range ty.Damage {
+		if damage.ID == defendID {
+			return damage.Scalar
+		}
+	}
+	return 1
+}
@@ -0,0 +1,290 @@
+package pogo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Diff is a report of the changes between two versions of the game data
+type Diff struct {
+	AddedPokemon   []string         `json:"addedPokemon,omitempty"`
+	RemovedPokemon []string         `json:"removedPokemon,omitempty"`
+	Stats          []StatChange     `json:"stats,omitempty"`
+	Movepools      []MovepoolChange `json:"movepools,omitempty"`
+	AddedMoves     []string         `json:"addedMoves,omitempty"`
+	RemovedMoves   []string         `json:"removedMoves,omitempty"`
+	Moves          []MoveChange     `json:"moves,omitempty"`
+	AddedTypes     []string         `json:"addedTypes,omitempty"`
+	RemovedTypes   []string         `json:"removedTypes,omitempty"`
+	Types          []TypeChange     `json:"types,omitempty"`
+}
+
+// StatChange is a change to the base stats of a pokemon
+type StatChange struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Old      PokemonStats `json:"old"`
+	New      PokemonStats `json:"new"`
+	OldMaxCP int          `json:"oldMaxCP"`
+	NewMaxCP int          `json:"newMaxCP"`
+}
+
+// MovepoolChange lists the moves a pokemon gained or lost
+type MovepoolChange struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	AddedFast     []string `json:"addedFast,omitempty"`
+	RemovedFast   []string `json:"removedFast,omitempty"`
+	AddedCharge   []string `json:"addedCharge,omitempty"`
+	RemovedCharge []string `json:"removedCharge,omitempty"`
+}
+
+// MoveChange is a rebalance of a move
+type MoveChange struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Old  *Move  `json:"old"`
+	New  *Move  `json:"new"`
+}
+
+// TypeChange is a change to the type chart
+type TypeChange struct {
+	Attacker string  `json:"attacker"`
+	Defender string  `json:"defender"`
+	Old      float64 `json:"old"`
+	New      float64 `json:"new"`
+}
+
+// Compare returns the differences between an old and a new Pokedex
+func Compare(oldDex *Pokedex, newDex *Pokedex) *Diff {
+	diff := &Diff{}
+
+	// Pokemon and forms
+	oldPokemon := map[string]Pokemon{}
+	for _, p := range oldDex.AllPokemon() {
+		oldPokemon[p.ID] = p
+	}
+	newPokemon := map[string]Pokemon{}
+	for _, p := range newDex.AllPokemon() {
+		newPokemon[p.ID] = p
+		old, ok := oldPokemon[p.ID]
+		if !ok {
+			diff.AddedPokemon = append(diff.AddedPokemon, p.ID)
+			continue
+		}
+
+		if old.Stats != p.Stats {
+			diff.Stats = append(diff.Stats, StatChange{
+				ID:       p.ID,
+				Name:     p.Name,
+				Old:      old.Stats,
+				New:      p.Stats,
+				OldMaxCP: old.GetCP(40, 15, 15, 15),
+				NewMaxCP: p.GetCP(40, 15, 15, 15),
+			})
+		}
+
+		change := MovepoolChange{ID: p.ID, Name: p.Name}
+		change.AddedFast, change.RemovedFast = compareMoveLists(old.Moves.Fast, p.Moves.Fast)
+		change.AddedCharge, change.RemovedCharge = compareMoveLists(old.Moves.Charge, p.Moves.Charge)
+		if len(change.AddedFast)+len(change.RemovedFast)+len(change.AddedCharge)+len(change.RemovedCharge) > 0 {
+			diff.Movepools = append(diff.Movepools, change)
+		}
+	}
+	for id := range oldPokemon {
+		if _, ok := newPokemon[id]; !ok {
+			diff.RemovedPokemon = append(diff.RemovedPokemon, id)
+		}
+	}
+
+	// Moves
+	for id, move := range newDex.moveMap {
+		old, ok := oldDex.moveMap[id]
+		if !ok {
+			diff.AddedMoves = append(diff.AddedMoves, id)
+			continue
+		}
+		if old.Power != move.Power || old.EnergyDelta != move.EnergyDelta || old.DurationMs != move.DurationMs || old.Combat != move.Combat {
+			diff.Moves = append(diff.Moves, MoveChange{ID: id, Name: move.Name, Old: old, New: move})
+		}
+	}
+	for id := range oldDex.moveMap {
+		if _, ok := newDex.moveMap[id]; !ok {
+			diff.RemovedMoves = append(diff.RemovedMoves, id)
+		}
+	}
+
+	// Types, by name like the type chart changes
+	for id := range newDex.typeMap {
+		if _, ok := oldDex.typeMap[id]; !ok {
+			diff.AddedTypes = append(diff.AddedTypes, typeName(id, newDex))
+		}
+	}
+	for id := range oldDex.typeMap {
+		if _, ok := newDex.typeMap[id]; !ok {
+			diff.RemovedTypes = append(diff.RemovedTypes, typeName(id, oldDex))
+		}
+	}
+
+	// Type chart, over every type either Pokedex knows as an attacker or a
+	// defender. Pairs missing from one side do normal damage there.
+	typeIDs := map[string]bool{}
+	for _, dex := range []*Pokedex{oldDex, newDex} {
+		for id, ty := range dex.typeMap {
+			typeIDs[id] = true
+			for _, damage := range ty.Damage {
+				typeIDs[damage.ID] = true
+			}
+		}
+	}
+	for attacker := range typeIDs {
+		for defender := range typeIDs {
+			old, scalar := oldDex.getScalar(attacker, defender), newDex.getScalar(attacker, defender)
+			if old != scalar {
+				diff.Types = append(diff.Types, TypeChange{
+					Attacker: typeName(attacker, newDex, oldDex),
+					Defender: typeName(defender, newDex, oldDex),
+					Old:      old,
+					New:      scalar,
+				})
+			}
+		}
+	}
+
+	sort.Strings(diff.AddedPokemon)
+	sort.Strings(diff.RemovedPokemon)
+	sort.Strings(diff.AddedMoves)
+	sort.Strings(diff.RemovedMoves)
+	sort.Strings(diff.AddedTypes)
+	sort.Strings(diff.RemovedTypes)
+	sort.Slice(diff.Stats, func(i, j int) bool { return diff.Stats[i].ID < diff.Stats[j].ID })
+	sort.Slice(diff.Movepools, func(i, j int) bool { return diff.Movepools[i].ID < diff.Movepools[j].ID })
+	sort.Slice(diff.Moves, func(i, j int) bool { return diff.Moves[i].ID < diff.Moves[j].ID })
+	sort.Slice(diff.Types, func(i, j int) bool {
+		if diff.Types[i].Attacker == diff.Types[j].Attacker {
+			return diff.Types[i].Defender < diff.Types[j].Defender
+		}
+		return diff.Types[i].Attacker < diff.Types[j].Attacker
+	})
+	return diff
+}
+
+// typeName returns the name of a type from the first Pokedex that has it, or its id
+func typeName(id string, dexes ...*Pokedex) string {
+	for _, dex := range dexes {
+		if ty, ok := dex.typeMap[id]; ok {
+			return ty.Name
+		}
+	}
+	return id
+}
+
+// compareMoveLists returns the ids of the moves added to and removed from a list
+func compareMoveLists(oldList MoveList, newList MoveList) (added []string, removed []string) {
+	oldIDs := map[string]bool{}
+	for _, m := range oldList {
+		oldIDs[m.ID] = true
+	}
+	newIDs := map[string]bool{}
+	for _, m := range newList {
+		newIDs[m.ID] = true
+		if !oldIDs[m.ID] {
+			added = append(added, m.ID)
+		}
+	}
+	for _, m := range oldList {
+		if !newIDs[m.ID] {
+			removed = append(removed, m.ID)
+		}
+	}
+	return
+}
+
+// Empty returns true if there are no differences
+func (diff *Diff) Empty() bool {
+	return len(diff.AddedPokemon)+len(diff.RemovedPokemon)+len(diff.Stats)+len(diff.Movepools)+
+		len(diff.AddedMoves)+len(diff.RemovedMoves)+len(diff.Moves)+
+		len(diff.AddedTypes)+len(diff.RemovedTypes)+len(diff.Types) == 0
+}
+
+// Print returns a human readable report of the differences
+func (diff *Diff) Print() string {
+	if diff.Empty() {
+		return "No changes."
+	}
+
+	lines := []string{}
+	if len(diff.AddedPokemon) > 0 {
+		lines = append(lines, "Added pokemon: "+strings.Join(diff.AddedPokemon, ", "))
+	}
+	if len(diff.RemovedPokemon) > 0 {
+		lines = append(lines, "Removed pokemon: "+strings.Join(diff.RemovedPokemon, ", "))
+	}
+	if len(diff.Stats) > 0 {
+		lines = append(lines, "Base stats (Atk/Def/Sta):")
+		for _, s := range diff.Stats {
+			lines = append(lines, fmt.Sprintf("  %s: %d/%d/%d -> %d/%d/%d, max CP %d -> %d (%+d)", s.Name,
+				s.Old.BaseAttack, s.Old.BaseDefense, s.Old.BaseStamina,
+				s.New.BaseAttack, s.New.BaseDefense, s.New.BaseStamina,
+				s.OldMaxCP, s.NewMaxCP, s.NewMaxCP-s.OldMaxCP))
+		}
+	}
+	if len(diff.Movepools) > 0 {
+		lines = append(lines, "Movepools:")
+		for _, m := range diff.Movepools {
+			changes := []string{}
+			for _, c := range []struct {
+				label string
+				moves []string
+			}{
+				{"+Fast", m.AddedFast}, {"-Fast", m.RemovedFast}, {"+Charge", m.AddedCharge}, {"-Charge", m.RemovedCharge},
+			} {
+				if len(c.moves) > 0 {
+					changes = append(changes, c.label+" "+strings.Join(c.moves, ", "))
+				}
+			}
+			lines = append(lines, fmt.Sprintf("  %s: %s", m.Name, strings.Join(changes, "; ")))
+		}
+	}
+	if len(diff.AddedMoves) > 0 {
+		lines = append(lines, "Added moves: "+strings.Join(diff.AddedMoves, ", "))
+	}
+	if len(diff.RemovedMoves) > 0 {
+		lines = append(lines, "Removed moves: "+strings.Join(diff.RemovedMoves, ", "))
+	}
+	if len(diff.Moves) > 0 {
+		lines = append(lines, "Moves:")
+		for _, m := range diff.Moves {
+			changes := []string{}
+			if m.Old.Power != m.New.Power {
+				changes = append(changes, fmt.Sprintf("power %v -> %v", m.Old.Power, m.New.Power))
+			}
+			if m.Old.EnergyDelta != m.New.EnergyDelta {
+				changes = append(changes, fmt.Sprintf("energy %d -> %d", m.Old.EnergyDelta, m.New.EnergyDelta))
+			}
+			if m.Old.DurationMs != m.New.DurationMs {
+				changes = append(changes, fmt.Sprintf("duration %dms -> %dms", m.Old.DurationMs, m.New.DurationMs))
+			}
+			if m.Old.Combat != m.New.Combat {
+				changes = append(changes, fmt.Sprintf("pvp power %v -> %v, pvp energy %d -> %d, turns %d -> %d",
+					m.Old.Combat.Power, m.New.Combat.Power, m.Old.Combat.EnergyDelta, m.New.Combat.EnergyDelta,
+					m.Old.Combat.DurationTurns, m.New.Combat.DurationTurns))
+			}
+			lines = append(lines, fmt.Sprintf("  %s: %s", m.Name, strings.Join(changes, ", ")))
+		}
+	}
+	if len(diff.AddedTypes) > 0 {
+		lines = append(lines, "Added types: "+strings.Join(diff.AddedTypes, ", "))
+	}
+	if len(diff.RemovedTypes) > 0 {
+		lines = append(lines, "Removed types: "+strings.Join(diff.RemovedTypes, ", "))
+	}
+	if len(diff.Types) > 0 {
+		lines = append(lines, "Type chart:")
+		for _, t := range diff.Types {
+			lines = append(lines, fmt.Sprintf("  %s -> %s: x%v -> x%v", t.Attacker, t.Defender, t.Old, t.New))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
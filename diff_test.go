@@ -0,0 +1,102 @@
+package pogo
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCompare(t *testing.T) {
+	oldDex, err := NewPokedex(fstest.MapFS{
+		"type.json": {Data: []byte(`[
+			{"id":"POKEMON_TYPE_NORMAL","name":"Normal","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1},{"id":"POKEMON_TYPE_FLYING","attackScalar":1}]},
+			{"id":"POKEMON_TYPE_FLYING","name":"Flying","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1},{"id":"POKEMON_TYPE_FLYING","attackScalar":1}]}]`)},
+		"move.json": {Data: []byte(`[{"id":"TACKLE_FAST","name":"Tackle Fast","power":5},{"id":"TWISTER","name":"Twister","power":45}]`)},
+		"pokemon.json": {Data: []byte(`[
+			{"id":"PIDGEY","name":"Pidgey","dex":16,"stats":{"baseAttack":85,"baseDefense":73,"baseStamina":120},"quickMoves":[{"id":"TACKLE_FAST"}],"cinematicMoves":[{"id":"TWISTER"}]},
+			{"id":"RATTATA","name":"Rattata","dex":19,"stats":{"baseAttack":103,"baseDefense":70,"baseStamina":102}}]`)},
+	})
+	if err != nil {
+		t.Fatal("Unable to load old pokedex:", err)
+	}
+
+	if diff := Compare(oldDex, oldDex); !diff.Empty() {
+		t.Error("Expected no changes, got", diff.Print())
+	}
+
+	newDex, err := NewPokedex(fstest.MapFS{
+		"type.json": {Data: []byte(`[
+			{"id":"POKEMON_TYPE_NORMAL","name":"Normal","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1},{"id":"POKEMON_TYPE_FLYING","attackScalar":0.625}]},
+			{"id":"POKEMON_TYPE_FLYING","name":"Flying","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1},{"id":"POKEMON_TYPE_FLYING","attackScalar":1}]}]`)},
+		"move.json": {Data: []byte(`[{"id":"TACKLE_FAST","name":"Tackle Fast","power":5},{"id":"TWISTER","name":"Twister","power":50},{"id":"GUST","name":"Gust","power":80}]`)},
+		"pokemon.json": {Data: []byte(`[
+			{"id":"PIDGEY","name":"Pidgey","dex":16,"stats":{"baseAttack":90,"baseDefense":73,"baseStamina":120},"quickMoves":[{"id":"TACKLE_FAST"}],"cinematicMoves":[{"id":"GUST"}]},
+			{"id":"SPEAROW","name":"Spearow","dex":21,"stats":{"baseAttack":112,"baseDefense":60,"baseStamina":120}}]`)},
+	})
+	if err != nil {
+		t.Fatal("Unable to load new pokedex:", err)
+	}
+
+	diff := Compare(oldDex, newDex)
+	if len(diff.AddedPokemon) != 1 || diff.AddedPokemon[0] != "spearow" {
+		t.Error("Expected spearow added, got", diff.AddedPokemon)
+	}
+	if len(diff.RemovedPokemon) != 1 || diff.RemovedPokemon[0] != "rattata" {
+		t.Error("Expected rattata removed, got", diff.RemovedPokemon)
+	}
+	if len(diff.Stats) != 1 || diff.Stats[0].NewMaxCP <= diff.Stats[0].OldMaxCP {
+		t.Error("Expected pidgey attack buff, got", diff.Stats)
+	}
+	if len(diff.Movepools) != 1 || diff.Movepools[0].AddedCharge[0] != "GUST" || diff.Movepools[0].RemovedCharge[0] != "TWISTER" {
+		t.Error("Expected pidgey to swap Twister for Gust, got", diff.Movepools)
+	}
+	if len(diff.AddedMoves) != 1 || len(diff.Moves) != 1 || diff.Moves[0].New.Power != 50 {
+		t.Error("Expected Gust added and Twister buffed, got", diff.AddedMoves, diff.Moves)
+	}
+	if len(diff.Types) != 1 || diff.Types[0].Attacker != "Normal" || diff.Types[0].Defender != "Flying" || diff.Types[0].New != 0.625 {
+		t.Error("Expected Normal to be resisted by Flying, got", diff.Types)
+	}
+}
+
+func TestCompareTypes(t *testing.T) {
+	files := func(types string) fstest.MapFS {
+		return fstest.MapFS{
+			"type.json":    {Data: []byte(types)},
+			"move.json":    {Data: []byte(`[{"id":"TACKLE_FAST","name":"Tackle Fast","power":5}]`)},
+			"pokemon.json": {Data: []byte(`[{"id":"RATTATA","name":"Rattata","dex":19,"stats":{"baseAttack":103,"baseDefense":70,"baseStamina":102}}]`)},
+		}
+	}
+	oldDex, err := NewPokedex(files(`[
+		{"id":"POKEMON_TYPE_NORMAL","name":"Normal","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1},{"id":"POKEMON_TYPE_GHOST","attackScalar":0.390625}]}]`))
+	if err != nil {
+		t.Fatal("Unable to load old pokedex:", err)
+	}
+	newDex, err := NewPokedex(files(`[
+		{"id":"POKEMON_TYPE_NORMAL","name":"Normal","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1}]},
+		{"id":"POKEMON_TYPE_FIGHTING","name":"Fighting","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1.6},{"id":"POKEMON_TYPE_FIGHTING","attackScalar":1}]}]`))
+	if err != nil {
+		t.Fatal("Unable to load new pokedex:", err)
+	}
+
+	diff := Compare(oldDex, newDex)
+	if len(diff.AddedTypes) != 1 || diff.AddedTypes[0] != "Fighting" {
+		t.Error("Expected fighting added, got", diff.AddedTypes)
+	}
+	if len(diff.Types) != 2 {
+		t.Fatal("Expected 2 type chart changes, got", diff.Types)
+	}
+	if c := diff.Types[0]; c.Attacker != "Fighting" || c.Defender != "Normal" || c.Old != 1 || c.New != 1.6 {
+		t.Error("Expected Fighting to be super effective against Normal, got", c)
+	}
+	if c := diff.Types[1]; c.Attacker != "Normal" || c.Defender != "POKEMON_TYPE_GHOST" || c.Old != 0.390625 || c.New != 1 {
+		t.Error("Expected Normal against Ghost to be removed, got", c)
+	}
+
+	diff = Compare(newDex, oldDex)
+	if len(diff.RemovedTypes) != 1 || diff.RemovedTypes[0] != "Fighting" {
+		t.Error("Expected fighting removed, got", diff.RemovedTypes)
+	}
+	if !strings.Contains(diff.Print(), "Removed types: Fighting\n") {
+		t.Error("Expected fighting removed by name, got", diff.Print())
+	}
+}
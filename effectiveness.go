@@ -0,0 +1,143 @@
+package pogo
+
+import (
+	"strings"
+)
+
+// NUM_TYPES is the number of pokemon types
+const NUM_TYPES = 18
+
+// TypeIDs lists every type in the game's order, which is the order of the
+// rows and columns of a TypeMatrix
+var TypeIDs = [NUM_TYPES]string{
+	"POKEMON_TYPE_NORMAL",
+	"POKEMON_TYPE_FIGHTING",
+	"POKEMON_TYPE_FLYING",
+	"POKEMON_TYPE_POISON",
+	"POKEMON_TYPE_GROUND",
+	"POKEMON_TYPE_ROCK",
+	"POKEMON_TYPE_BUG",
+	"POKEMON_TYPE_GHOST",
+	"POKEMON_TYPE_STEEL",
+	"POKEMON_TYPE_FIRE",
+	"POKEMON_TYPE_WATER",
+	"POKEMON_TYPE_GRASS",
+	"POKEMON_TYPE_ELECTRIC",
+	"POKEMON_TYPE_PSYCHIC",
+	"POKEMON_TYPE_ICE",
+	"POKEMON_TYPE_DRAGON",
+	"POKEMON_TYPE_DARK",
+	"POKEMON_TYPE_FAIRY",
+}
+
+// typeIndex maps a type id to its position in TypeIDs
+var typeIndex = func() map[string]int {
+	index := make(map[string]int, NUM_TYPES)
+	for i, id := range TypeIDs {
+		index[id] = i
+	}
+	return index
+}()
+
+// TypeMatrix holds the damage scalar of every type attacking every other
+// type, as matrix[attack][defend] with both indexed as in TypeIDs
+type TypeMatrix [NUM_TYPES][NUM_TYPES]float64
+
+// newTypeMatrix builds the matrix from the scalars of each type. Pairs the
+// game data doesn't give do normal damage.
+func newTypeMatrix(typeMap map[string]Type) TypeMatrix {
+	var m TypeMatrix
+	for a := range m {
+		for d := range m[a] {
+			m[a][d] = 1
+		}
+	}
+	for id, ty := range typeMap {
+		a, ok := typeIndex[id]
+		if !ok {
+			continue
+		}
+		for _, damage := range ty.Damage {
+			if d, ok := typeIndex[damage.ID]; ok {
+				m[a][d] = damage.Scalar
+			}
+		}
+	}
+	return m
+}
+
+// Get returns the damage scalar of one type id attacking another
+func (m *TypeMatrix) Get(attackID string, defendID string) float64 {
+	a, ok := typeIndex[attackID]
+	if !ok {
+		return 1
+	}
+	d, ok := typeIndex[defendID]
+	if !ok {
+		return 1
+	}
+	return m[a][d]
+}
+
+// GetTypeMatrix returns the type matrix of the default Pokedex, or an error
+// if it can't be loaded
+func GetTypeMatrix() (TypeMatrix, error) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		return TypeMatrix{}, err
+	}
+	return d.GetTypeMatrix(), nil
+}
+
+// GetTypeMatrix returns the damage scalar of every type attacking every other type
+func (d *Pokedex) GetTypeMatrix() TypeMatrix {
+	return d.typeMatrix
+}
+
+// Effectiveness returns the damage multiplier of an attack type against a
+// pokemon with the defender types in the default Pokedex, or an error if it
+// can't be loaded
+func Effectiveness(attackType string, defenderTypes ...string) (float64, error) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		return 0, err
+	}
+	return d.Effectiveness(attackType, defenderTypes...), nil
+}
+
+// Effectiveness returns the damage multiplier of an attack type against a
+// pokemon with the defender types, such as 1.6 or 0.390625, multiplied
+// together for dual types. Types can be given by id or name, and unknown
+// types do normal damage.
+func (d *Pokedex) Effectiveness(attackType string, defenderTypes ...string) float64 {
+	attackID := d.typeID(attackType)
+	effectiveness := 1.0
+	for _, t := range defenderTypes {
+		effectiveness *= d.getScalar(attackID, d.typeID(t))
+	}
+	return effectiveness
+}
+
+// GetEffectiveness returns the damage multiplier of an attack type against the pokemon
+func (p *Pokemon) GetEffectiveness(attackType string) float64 {
+	types := []string{}
+	for _, t := range p.Types {
+		types = append(types, t.ID)
+	}
+	d, _ := p.getPokedex()
+	return d.Effectiveness(attackType, types...)
+}
+
+// typeID returns the id of a type given by id or name
+func (d *Pokedex) typeID(t string) string {
+	if d == nil {
+		return t
+	}
+	if _, ok := d.typeMap[t]; ok {
+		return t
+	}
+	if id, ok := d.typeToID[strings.ToLower(t)]; ok {
+		return id
+	}
+	return t
+}
@@ -0,0 +1,83 @@
+package pogo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEffectiveness(t *testing.T) {
+	tests := []struct {
+		attack   string
+		defend   []string
+		expected float64
+	}{
+		{"fire", []string{"grass"}, 1.6},
+		{"POKEMON_TYPE_FIRE", []string{"POKEMON_TYPE_GRASS", "POKEMON_TYPE_BUG"}, 2.56},
+		{"Fire", []string{"Water", "Dragon"}, 0.390625},
+		{"normal", []string{"ghost"}, 0.390625},
+		{"water", []string{"fire", "grass"}, 1},
+		{"electric", []string{"water", "flying"}, 2.56},
+		{"fire", nil, 1},
+		{"splash", []string{"grass"}, 1},
+		{"fire", []string{"splash"}, 1},
+	}
+	for _, test := range tests {
+		if e, err := Effectiveness(test.attack, test.defend...); err != nil || math.Abs(e-test.expected) > 1e-9 {
+			t.Error("For", test.attack, "against", test.defend, "expected", test.expected, "got", e, err)
+		}
+	}
+
+	gyarados, err := GetPokemon("gyarados")
+	if err != nil {
+		t.Fatal("Unable to get gyarados:", err)
+	}
+	if e := gyarados.GetEffectiveness("electric"); math.Abs(e-2.56) > 1e-9 {
+		t.Error("Expected 2.56 electric against gyarados, got", e)
+	}
+}
+
+func TestTypeMatrix(t *testing.T) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		t.Fatal("Unable to load pokedex:", err)
+	}
+	m := d.GetTypeMatrix()
+	for a, attackID := range TypeIDs {
+		scalars := d.typeMap[attackID].Damage
+		if len(scalars) != NUM_TYPES {
+			t.Fatal("Expected", NUM_TYPES, "scalars for", attackID, "got", len(scalars))
+		}
+		for _, damage := range scalars {
+			if s := m[a][typeIndex[damage.ID]]; s != damage.Scalar || m.Get(attackID, damage.ID) != s {
+				t.Error("For", attackID, "against", damage.ID, "expected", damage.Scalar, "got", s)
+			}
+		}
+	}
+	if s := m.Get("POKEMON_TYPE_FIRE", "POKEMON_TYPE_SPLASH"); s != 1 {
+		t.Error("Expected normal damage against an unknown type, got", s)
+	}
+	if def, err := GetTypeMatrix(); err != nil || def != m {
+		t.Error("Expected the default type matrix, got", err)
+	}
+}
+
+func TestEffectivenessWithoutData(t *testing.T) {
+	withBrokenDefault(t, func() {
+		if _, err := GetTypeMatrix(); err == nil {
+			t.Error("Expected an error for the type matrix")
+		}
+		if _, err := Effectiveness("fire", "grass"); err == nil {
+			t.Error("Expected an error for effectiveness")
+		}
+	})
+}
+
+func BenchmarkEffectiveness(b *testing.B) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		b.Fatal("Unable to load pokedex:", err)
+	}
+	for i := 0; i < b.N; i++ {
+		d.Effectiveness("POKEMON_TYPE_FIRE", "POKEMON_TYPE_GRASS", "POKEMON_TYPE_BUG")
+	}
+}
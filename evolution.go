@@ -0,0 +1,256 @@
+package pogo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Errors
+var (
+	ERR_FAMILY_NOT_FOUND = errors.New("Family not found.")
+)
+
+// Family is the candy family a pokemon belongs to
+type Family struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Evolution holds where a pokemon evolves from and into
+type Evolution struct {
+	FutureBranches []*EvolutionBranch `json:"futureBranches,omitempty"`
+	PastBranch     *EvolutionBranch   `json:"pastBranch,omitempty"`
+	CostToEvolve   *EvolutionCost     `json:"costToEvolve,omitempty"`
+}
+
+// EvolutionBranch is a pokemon that can be evolved into or from
+type EvolutionBranch struct {
+	ID             string             `json:"id"`
+	Name           string             `json:"name"`
+	FutureBranches []*EvolutionBranch `json:"futureBranches,omitempty"`
+	CostToEvolve   *EvolutionCost     `json:"costToEvolve,omitempty"`
+}
+
+// EvolutionCost is what it takes to evolve a pokemon
+type EvolutionCost struct {
+	CandyCost     int     `json:"candyCost"`
+	EvolutionItem *Item   `json:"evolutionItem,omitempty"`
+	BuddyDistance float64 `json:"buddyDistance,omitempty"`
+	MustBeBuddy   bool    `json:"mustBeBuddy,omitempty"`
+	OnlyDaytime   bool    `json:"onlyDaytime,omitempty"`
+	OnlyNighttime bool    `json:"onlyNighttime,omitempty"`
+	LureItem      *Item   `json:"lureItem,omitempty"`
+	Gender        string  `json:"gender,omitempty"`
+}
+
+// Item is an item needed for an evolution
+type Item struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// EvolutionStep is a single evolution from one pokemon into another
+type EvolutionStep struct {
+	From *Pokemon
+	To   *Pokemon
+	EvolutionCost
+}
+
+// Evolutions returns the pokemon this pokemon can evolve into
+func (p *Pokemon) Evolutions() []EvolutionStep {
+	steps := []EvolutionStep{}
+	d, err := p.getPokedex()
+	if err != nil {
+		return steps
+	}
+	for _, branch := range p.Evolution.FutureBranches {
+		to, err := d.GetPokemon(pokemonKey(branch.ID))
+		if err != nil {
+			continue
+		}
+		step := EvolutionStep{From: p, To: to}
+		if branch.CostToEvolve != nil {
+			step.EvolutionCost = *branch.CostToEvolve
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// PreEvolution returns the evolution into this pokemon, or nil if it doesn't
+// evolve from anything
+func (p *Pokemon) PreEvolution() *EvolutionStep {
+	if p.Evolution.PastBranch == nil {
+		return nil
+	}
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil
+	}
+	from, err := d.GetPokemon(pokemonKey(p.Evolution.PastBranch.ID))
+	if err != nil {
+		return nil
+	}
+
+	// The branch on the pre-evolution has the full cost, including items
+	for _, step := range from.Evolutions() {
+		if step.To.ID == p.ID {
+			step.To = p
+			return &step
+		}
+	}
+	step := &EvolutionStep{From: from, To: p}
+	if p.Evolution.CostToEvolve != nil {
+		step.EvolutionCost = *p.Evolution.CostToEvolve
+	}
+	return step
+}
+
+// EvolutionChains returns every evolution path in the pokemon's evolution
+// tree, from its first stage to each final evolution
+func (p *Pokemon) EvolutionChains() [][]EvolutionStep {
+	root := p
+	seen := map[string]bool{root.ID: true}
+	for pre := root.PreEvolution(); pre != nil && !seen[pre.From.ID]; pre = root.PreEvolution() {
+		root = pre.From
+		seen[root.ID] = true
+	}
+
+	chains := [][]EvolutionStep{}
+	var walk func(poke *Pokemon, chain []EvolutionStep, seen map[string]bool)
+	walk = func(poke *Pokemon, chain []EvolutionStep, seen map[string]bool) {
+		evolved := false
+		for _, step := range poke.Evolutions() {
+			if seen[step.To.ID] {
+				continue
+			}
+			evolved = true
+			seen[step.To.ID] = true
+			walk(step.To, append(chain[:len(chain):len(chain)], step), seen)
+			delete(seen, step.To.ID)
+		}
+		if !evolved && len(chain) > 0 {
+			chains = append(chains, chain)
+		}
+	}
+	walk(root, nil, map[string]bool{root.ID: true})
+	return chains
+}
+
+// Family returns every species sharing the pokemon's candy
+func (p *Pokemon) Family() []Pokemon {
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil
+	}
+	family, _ := d.GetFamily(p.CandyFamily.ID)
+	return family
+}
+
+// GetFamily returns every species in a candy family from the default Pokedex
+func GetFamily(family string) ([]Pokemon, error) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		return nil, err
+	}
+	return d.GetFamily(family)
+}
+
+// GetFamily returns every species in a candy family, by family id such as
+// FAMILY_ODDISH, family name, or the name of any pokemon in the family.
+// Shadow and purified pokemon are left out.
+func (d *Pokedex) GetFamily(family string) ([]Pokemon, error) {
+	id := familyKey(family)
+	if _, ok := d.familyMap[id]; !ok {
+		p, err := d.GetPokemon(family)
+		if err != nil {
+			return nil, ERR_FAMILY_NOT_FOUND
+		}
+		id = familyKey(p.CandyFamily.ID)
+	}
+
+	ids, ok := d.familyMap[id]
+	if !ok {
+		return nil, ERR_FAMILY_NOT_FOUND
+	}
+	members := []Pokemon{}
+	for _, pokeID := range ids {
+		p := d.pokemonMap[pokeID]
+		p.Moves = p.Moves.clone()
+		members = append(members, p)
+	}
+	return members, nil
+}
+
+// Families returns every candy family, sorted by id
+func (d *Pokedex) Families() []Family {
+	families := []Family{}
+	for _, ids := range d.familyMap {
+		families = append(families, d.pokemonMap[ids[0]].CandyFamily)
+	}
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].ID < families[j].ID
+	})
+	return families
+}
+
+// loadFamilies indexes the species in each candy family
+func (d *Pokedex) loadFamilies() {
+	d.familyMap = make(map[string][]string)
+	for _, poke := range d.pokemonList {
+		if poke.CandyFamily.ID == "" || poke.Variant != VARIANT_NORMAL {
+			continue
+		}
+		id := familyKey(poke.CandyFamily.ID)
+		d.familyMap[id] = append(d.familyMap[id], poke.ID)
+	}
+}
+
+// familyKey turns a family id or name such as "Mr. Mime" into FAMILY_MR_MIME
+func familyKey(family string) string {
+	family = strings.ToUpper(strings.NewReplacer("-", "_", " ", "_", ".", "").Replace(family))
+	if !strings.HasPrefix(family, "FAMILY_") {
+		family = "FAMILY_" + family
+	}
+	return family
+}
+
+// Requirements returns the candy, item and any other conditions needed for
+// the evolution
+func (c EvolutionCost) Requirements() []string {
+	reqs := []string{}
+	if c.CandyCost > 0 {
+		reqs = append(reqs, fmt.Sprintf("%d candy", c.CandyCost))
+	}
+	if c.EvolutionItem != nil {
+		reqs = append(reqs, c.EvolutionItem.Name)
+	}
+	if c.LureItem != nil {
+		reqs = append(reqs, c.LureItem.Name)
+	}
+	if c.BuddyDistance > 0 {
+		reqs = append(reqs, fmt.Sprintf("walk %gkm as buddy", c.BuddyDistance))
+	} else if c.MustBeBuddy {
+		reqs = append(reqs, "must be buddy")
+	}
+	if c.OnlyDaytime {
+		reqs = append(reqs, "daytime only")
+	}
+	if c.OnlyNighttime {
+		reqs = append(reqs, "nighttime only")
+	}
+	if c.Gender != "" {
+		reqs = append(reqs, strings.ToLower(c.Gender)+" only")
+	}
+	return reqs
+}
+
+func (s EvolutionStep) String() string {
+	str := fmt.Sprintf("%s -> %s", s.From.Name, s.To.Name)
+	if reqs := s.Requirements(); len(reqs) > 0 {
+		str += " (" + strings.Join(reqs, ", ") + ")"
+	}
+	return str
+}
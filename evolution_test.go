@@ -0,0 +1,64 @@
+package pogo
+
+import (
+	"testing"
+)
+
+func TestEvolutions(t *testing.T) {
+	gloom, err := GetPokemon("gloom")
+	if err != nil {
+		t.Fatal("Unable to get gloom:", err)
+	}
+
+	pre := gloom.PreEvolution()
+	if pre == nil || pre.From.ID != "oddish" || pre.CandyCost != 25 {
+		t.Fatal("Expected Oddish -> Gloom for 25 candy, got", pre)
+	}
+	if oddish := pre.From; oddish.PreEvolution() != nil {
+		t.Error("Expected oddish to have no pre evolution")
+	}
+
+	steps := gloom.Evolutions()
+	if len(steps) != 2 {
+		t.Fatal("Expected 2 evolutions for gloom, got", steps)
+	}
+	if s := steps[1].String(); s != "Gloom -> Bellossom (100 candy, Sun Stone)" {
+		t.Error("Expected Bellossom with a Sun Stone, got", s)
+	}
+
+	chains := gloom.EvolutionChains()
+	if len(chains) != 2 {
+		t.Fatal("Expected 2 evolution chains for gloom, got", chains)
+	}
+	for _, chain := range chains {
+		if len(chain) != 2 || chain[0].From.ID != "oddish" || chain[0].To.ID != "gloom" {
+			t.Error("Expected chain from oddish through gloom, got", chain)
+		}
+	}
+
+	if shadow, err := GetPokemon("bulbasaur-shadow"); err != nil || len(shadow.Evolutions()) != 1 || shadow.Evolutions()[0].To.ID != "ivysaur-shadow" {
+		t.Error("Expected bulbasaur shadow to evolve into ivysaur shadow")
+	}
+}
+
+func TestGetFamily(t *testing.T) {
+	for _, name := range []string{"FAMILY_ODDISH", "oddish", "bellossom"} {
+		family, err := GetFamily(name)
+		if err != nil {
+			t.Error("For", name, "unable to get family:", err)
+			continue
+		}
+		if len(family) != 4 || family[0].ID != "oddish" {
+			t.Error("For", name, "expected the 4 oddish species, got", len(family))
+		}
+	}
+
+	if _, err := GetFamily("missingno"); err != ERR_FAMILY_NOT_FOUND {
+		t.Error("Expected family not found, got", err)
+	}
+
+	pichu, _ := GetPokemon("pichu")
+	if family := pichu.Family(); len(family) == 0 || family[0].CandyFamily.Name != "Pikachu" {
+		t.Error("Expected pichu in the pikachu family, got", family)
+	}
+}
@@ -0,0 +1,439 @@
+package pogo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ERR_GAME_MASTER = errors.New("Unrecognized GAME_MASTER format.")
+)
+
+// gameMasterNames holds the pokemon whose names can't be made from their id
+var gameMasterNames = map[string]string{
+	"MR_MIME":        "Mr. Mime",
+	"MR_RIME":        "Mr. Rime",
+	"HO_OH":          "Ho-Oh",
+	"FARFETCHD":      "Farfetch'd",
+	"SIRFETCHD":      "Sirfetch'd",
+	"NIDORAN_FEMALE": "Nidoran",
+	"NIDORAN_MALE":   "Nidoran",
+}
+
+var gameMasterDex = regexp.MustCompile(`^(?:FORMS_)?V(\d{4})_POKEMON_`)
+
+// gameMasterID is a template id, which newer GAME_MASTER files sometimes give as a number
+type gameMasterID string
+
+func (id *gameMasterID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = gameMasterID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = gameMasterID(n.String())
+	return nil
+}
+
+type gameMasterTemplate struct {
+	TemplateID      string                     `json:"templateId"`
+	PokemonSettings *gameMasterPokemonSettings `json:"pokemonSettings"`
+	FormSettings    *gameMasterFormSettings    `json:"formSettings"`
+	MoveSettings    *gameMasterMoveSettings    `json:"moveSettings"`
+	CombatMove      *gameMasterCombatMove      `json:"combatMove"`
+	TypeEffective   *gameMasterTypeEffective   `json:"typeEffective"`
+	PlayerLevel     *gameMasterPlayerLevel     `json:"playerLevel"`
+}
+
+type gameMasterPokemonSettings struct {
+	PokemonID        gameMasterID                `json:"pokemonId"`
+	Form             gameMasterID                `json:"form"`
+	Type             string                      `json:"type"`
+	Type2            string                      `json:"type2"`
+	Stats            PokemonStats                `json:"stats"`
+	QuickMoves       []gameMasterID              `json:"quickMoves"`
+	CinematicMoves   []gameMasterID              `json:"cinematicMoves"`
+	FamilyID         string                      `json:"familyId"`
+	ParentPokemonID  gameMasterID                `json:"parentPokemonId"`
+	EvolutionBranch  []gameMasterEvolutionBranch `json:"evolutionBranch"`
+	Encounter        gameMasterEncounter         `json:"encounter"`
+	TempEvoOverrides []gameMasterTempEvo         `json:"tempEvoOverrides"`
+}
+
+type gameMasterTempEvo struct {
+	TempEvoID     string       `json:"tempEvoId"`
+	Stats         PokemonStats `json:"stats"`
+	TypeOverride1 string       `json:"typeOverride1"`
+	TypeOverride2 string       `json:"typeOverride2"`
+}
+
+type gameMasterEncounter struct {
+	BaseCaptureRate   float64 `json:"baseCaptureRate"`
+	BaseFleeRate      float64 `json:"baseFleeRate"`
+	AttackProbability float64 `json:"attackProbability"`
+	DodgeProbability  float64 `json:"dodgeProbability"`
+	MovementType      string  `json:"movementType"`
+}
+
+type gameMasterEvolutionBranch struct {
+	Evolution                  gameMasterID `json:"evolution"`
+	Form                       gameMasterID `json:"form"`
+	CandyCost                  int          `json:"candyCost"`
+	EvolutionItemRequirement   string       `json:"evolutionItemRequirement"`
+	KmBuddyDistanceRequirement float64      `json:"kmBuddyDistanceRequirement"`
+	MustBeBuddy                bool         `json:"mustBeBuddy"`
+	OnlyDaytime                bool         `json:"onlyDaytime"`
+	OnlyNighttime              bool         `json:"onlyNighttime"`
+	LureItemRequirement        string       `json:"lureItemRequirement"`
+	GenderRequirement          string       `json:"genderRequirement"`
+
+	// Mega evolution branches only give the temporary evolution and its cost
+	TemporaryEvolution                     string `json:"temporaryEvolution"`
+	TemporaryEvolutionEnergyCost           int    `json:"temporaryEvolutionEnergyCost"`
+	TemporaryEvolutionEnergyCostSubsequent int    `json:"temporaryEvolutionEnergyCostSubsequent"`
+}
+
+type gameMasterFormSettings struct {
+	Pokemon gameMasterID `json:"pokemon"`
+	Forms   []struct {
+		Form              gameMasterID `json:"form"`
+		AssetBundleValue  int          `json:"assetBundleValue"`
+		AssetBundleSuffix string       `json:"assetBundleSuffix"`
+	} `json:"forms"`
+}
+
+type gameMasterMoveSettings struct {
+	MovementID          gameMasterID `json:"movementId"`
+	PokemonType         string       `json:"pokemonType"`
+	Power               float64      `json:"power"`
+	EnergyDelta         int          `json:"energyDelta"`
+	DurationMs          int          `json:"durationMs"`
+	DamageWindowStartMs int          `json:"damageWindowStartMs"`
+	DamageWindowEndMs   int          `json:"damageWindowEndMs"`
+	CriticalChance      float64      `json:"criticalChance"`
+	StaminaLossScalar   float64      `json:"staminaLossScalar"`
+}
+
+type gameMasterCombatMove struct {
+	UniqueID      gameMasterID `json:"uniqueId"`
+	Power         float64      `json:"power"`
+	EnergyDelta   int          `json:"energyDelta"`
+	DurationTurns int          `json:"durationTurns"`
+}
+
+type gameMasterTypeEffective struct {
+	AttackScalar []float64 `json:"attackScalar"`
+	AttackType   string    `json:"attackType"`
+}
+
+type gameMasterPlayerLevel struct {
+	CPMultiplier []float64 `json:"cpMultiplier"`
+}
+
+// ParseGameMaster builds a Pokedex from a raw GAME_MASTER json dump. Both the
+// older {"itemTemplates": [...]} layout and the newer list of
+// {"templateId": ..., "data": {...}} entries are understood.
+func ParseGameMaster(r io.Reader) (*Pokedex, error) {
+	file, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	templates, err := readGameMasterTemplates(file)
+	if err != nil {
+		return nil, err
+	}
+
+	typeList := []Type{}
+	moveList := []*Move{}
+	moves := map[string]*Move{}
+	pokemonList := []Pokemon{}
+	pokemonBases := []string{}
+	pokemonIndex := map[string]int{}
+	forms := map[string]FormList{}
+	cpMultipliers := []float64{}
+
+	for _, t := range templates {
+		switch {
+		case t.TypeEffective != nil:
+			ty := Type{
+				ID:   t.TypeEffective.AttackType,
+				Name: gameMasterTypeName(t.TypeEffective.AttackType),
+			}
+			for i, scalar := range t.TypeEffective.AttackScalar {
+				// The scalars are in the order of TypeIDs
+				if i < len(TypeIDs) {
+					ty.Damage = append(ty.Damage, &TypeDamage{ID: TypeIDs[i], Scalar: scalar})
+				}
+			}
+			typeList = append(typeList, ty)
+
+		case t.MoveSettings != nil:
+			settings := t.MoveSettings
+			id := string(settings.MovementID)
+			move, ok := moves[id]
+			if !ok {
+				move = &Move{ID: id}
+				moves[id] = move
+				moveList = append(moveList, move)
+			}
+			move.Name = gameMasterName(id)
+			move.Type = PokemonType{ID: settings.PokemonType, Name: gameMasterTypeName(settings.PokemonType)}
+			move.Power = settings.Power
+			move.EnergyDelta = settings.EnergyDelta
+			move.DurationMs = settings.DurationMs
+			move.DamageWindowStartMs = settings.DamageWindowStartMs
+			move.DamageWindowEndMs = settings.DamageWindowEndMs
+			move.CriticalChance = settings.CriticalChance
+			move.StaminaLossScalar = settings.StaminaLossScalar
+
+		case t.CombatMove != nil:
+			id := string(t.CombatMove.UniqueID)
+			move, ok := moves[id]
+			if !ok {
+				move = &Move{ID: id, Name: gameMasterName(id)}
+				moves[id] = move
+				moveList = append(moveList, move)
+			}
+			move.Combat = CombatMove{
+				Power:         t.CombatMove.Power,
+				EnergyDelta:   t.CombatMove.EnergyDelta,
+				DurationTurns: t.CombatMove.DurationTurns,
+			}
+
+		case t.PlayerLevel != nil:
+			cpMultipliers = t.PlayerLevel.CPMultiplier
+
+		case t.FormSettings != nil:
+			base := string(t.FormSettings.Pokemon)
+			for _, f := range t.FormSettings.Forms {
+				id := gameMasterFormID(base, string(f.Form))
+				forms[base] = append(forms[base], &PokemonForm{
+					ID:                id,
+					Name:              gameMasterPokemonName(base, id),
+					AssetBundleValue:  f.AssetBundleValue,
+					AssetBundleSuffix: f.AssetBundleSuffix,
+				})
+			}
+
+		case t.PokemonSettings != nil:
+			settings := t.PokemonSettings
+			base := string(settings.PokemonID)
+			id := gameMasterFormID(base, string(settings.Form))
+			if _, ok := pokemonIndex[id]; ok {
+				continue
+			}
+
+			poke := Pokemon{
+				ID:    id,
+				Name:  gameMasterPokemonName(base, id),
+				Dex:   gameMasterDexNumber(t.TemplateID),
+				Stats: settings.Stats,
+			}
+			for _, ty := range []string{settings.Type, settings.Type2} {
+				if ty != "" {
+					poke.Types = append(poke.Types, &PokemonType{ID: ty, Name: gameMasterTypeName(ty)})
+				}
+			}
+			for _, m := range settings.QuickMoves {
+				poke.Moves.Fast = append(poke.Moves.Fast, &Move{ID: string(m), Name: gameMasterName(string(m))})
+			}
+			for _, m := range settings.CinematicMoves {
+				poke.Moves.Charge = append(poke.Moves.Charge, &Move{ID: string(m), Name: gameMasterName(string(m))})
+			}
+			poke.Encounter = Encounter{
+				BaseCaptureRate:   settings.Encounter.BaseCaptureRate,
+				BaseFleeRate:      settings.Encounter.BaseFleeRate,
+				AttackProbability: settings.Encounter.AttackProbability,
+				DodgeProbability:  settings.Encounter.DodgeProbability,
+			}
+			if movement := settings.Encounter.MovementType; movement != "" {
+				poke.Encounter.MovementType = &MovementType{ID: movement, Name: gameMasterName(movement)}
+			}
+			if settings.FamilyID != "" {
+				family := strings.TrimPrefix(settings.FamilyID, "FAMILY_")
+				poke.CandyFamily = Family{ID: settings.FamilyID, Name: gameMasterPokemonName(family, family)}
+			}
+			if parent := string(settings.ParentPokemonID); parent != "" {
+				poke.Evolution.PastBranch = &EvolutionBranch{ID: parent, Name: gameMasterPokemonName(parent, parent)}
+			}
+			for _, b := range settings.EvolutionBranch {
+				if b.TemporaryEvolution == "" {
+					poke.Evolution.FutureBranches = append(poke.Evolution.FutureBranches, b.branch())
+				}
+			}
+			poke.MegaEvolutions = gameMasterMegaEvolutions(settings)
+
+			pokemonIndex[id] = len(pokemonList)
+			pokemonList = append(pokemonList, poke)
+			pokemonBases = append(pokemonBases, base)
+		}
+	}
+
+	var levelMap map[float64]float64
+	if len(cpMultipliers) > 0 {
+		levelMap = newMultiplierMap(cpMultipliers)
+	}
+	// Point each evolution back at the form it evolves from
+	for i := range pokemonList {
+		for _, branch := range pokemonList[i].Evolution.FutureBranches {
+			if j, ok := pokemonIndex[branch.ID]; ok {
+				pokemonList[j].Evolution.PastBranch = &EvolutionBranch{ID: pokemonList[i].ID, Name: pokemonList[i].Name}
+				pokemonList[j].Evolution.CostToEvolve = branch.CostToEvolve
+			}
+		}
+	}
+
+	for i := range pokemonList {
+		poke := &pokemonList[i]
+		poke.Forms = forms[pokemonBases[i]]
+	}
+
+	return newPokedex(typeList, moveList, pokemonList, levelMap)
+}
+
+// branch returns the evolution branch and its requirements
+func (b gameMasterEvolutionBranch) branch() *EvolutionBranch {
+	base := string(b.Evolution)
+	id := gameMasterFormID(base, string(b.Form))
+	cost := &EvolutionCost{
+		CandyCost:     b.CandyCost,
+		EvolutionItem: gameMasterItem(b.EvolutionItemRequirement),
+		BuddyDistance: b.KmBuddyDistanceRequirement,
+		MustBeBuddy:   b.MustBeBuddy,
+		OnlyDaytime:   b.OnlyDaytime,
+		OnlyNighttime: b.OnlyNighttime,
+		LureItem:      gameMasterItem(b.LureItemRequirement),
+		Gender:        b.GenderRequirement,
+	}
+	return &EvolutionBranch{ID: id, Name: gameMasterPokemonName(base, id), CostToEvolve: cost}
+}
+
+// gameMasterMegaEvolutions returns the mega evolutions of a pokemon, with
+// their stats and types from tempEvoOverrides and their cost from the
+// evolution branches
+func gameMasterMegaEvolutions(settings *gameMasterPokemonSettings) []*MegaEvolution {
+	megas := []*MegaEvolution{}
+	for _, o := range settings.TempEvoOverrides {
+		if o.TempEvoID == "" {
+			continue
+		}
+		mega := &MegaEvolution{ID: o.TempEvoID, Stats: o.Stats}
+		for _, ty := range []string{o.TypeOverride1, o.TypeOverride2} {
+			if ty != "" {
+				mega.Types = append(mega.Types, &PokemonType{ID: ty, Name: gameMasterTypeName(ty)})
+			}
+		}
+		for _, b := range settings.EvolutionBranch {
+			if b.TemporaryEvolution == o.TempEvoID {
+				mega.EnergyCost = b.TemporaryEvolutionEnergyCost
+				mega.EnergyCostSubsequent = b.TemporaryEvolutionEnergyCostSubsequent
+			}
+		}
+		megas = append(megas, mega)
+	}
+	if len(megas) == 0 {
+		return nil
+	}
+	return megas
+}
+
+// readGameMasterTemplates returns the templates from either GAME_MASTER layout
+func readGameMasterTemplates(file []byte) ([]gameMasterTemplate, error) {
+	file = bytes.TrimSpace(file)
+	if len(file) == 0 {
+		return nil, ERR_GAME_MASTER
+	}
+
+	templates := []gameMasterTemplate{}
+	if file[0] == '{' {
+		gameMaster := struct {
+			ItemTemplates []gameMasterTemplate `json:"itemTemplates"`
+		}{}
+		if err := json.Unmarshal(file, &gameMaster); err != nil {
+			return nil, err
+		}
+		templates = gameMaster.ItemTemplates
+	} else {
+		entries := []struct {
+			TemplateID string             `json:"templateId"`
+			Data       gameMasterTemplate `json:"data"`
+		}{}
+		if err := json.Unmarshal(file, &entries); err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Data.TemplateID == "" {
+				entry.Data.TemplateID = entry.TemplateID
+			}
+			templates = append(templates, entry.Data)
+		}
+	}
+
+	if len(templates) == 0 {
+		return nil, ERR_GAME_MASTER
+	}
+	return templates, nil
+}
+
+// gameMasterFormID returns the id used for a pokemon form, where the
+// normal form shares the id of the pokemon itself
+func gameMasterFormID(base string, form string) string {
+	if form == "" || form == base+"_NORMAL" {
+		return base
+	}
+	return form
+}
+
+// gameMasterName turns an id such as VINE_WHIP_FAST into Vine Whip Fast
+func gameMasterName(id string) string {
+	words := strings.Split(strings.ToLower(id), "_")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// gameMasterPokemonName names a pokemon or form such as RATTATA_ALOLA
+func gameMasterPokemonName(base string, id string) string {
+	name, ok := gameMasterNames[base]
+	if !ok {
+		name = gameMasterName(base)
+	}
+	if id != base && strings.HasPrefix(id, base+"_") {
+		name += " " + gameMasterName(strings.TrimPrefix(id, base+"_"))
+	}
+	return name
+}
+
+func gameMasterItem(id string) *Item {
+	if id == "" {
+		return nil
+	}
+	return &Item{ID: id, Name: gameMasterName(strings.TrimPrefix(id, "ITEM_"))}
+}
+
+func gameMasterTypeName(id string) string {
+	return gameMasterName(strings.TrimPrefix(id, "POKEMON_TYPE_"))
+}
+
+func gameMasterDexNumber(templateID string) int {
+	match := gameMasterDex.FindStringSubmatch(templateID)
+	if match == nil {
+		return 0
+	}
+	dex, _ := strconv.Atoi(match[1])
+	return dex
+}
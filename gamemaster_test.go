@@ -0,0 +1,86 @@
+package pogo
+
+import (
+	"strings"
+	"testing"
+)
+
+var testGameMasterTemplates = `
+	{"templateId": "POKEMON_TYPE_NORMAL", "typeEffective": {"attackScalar": [1, 1, 1, 1, 1, 0.625, 1, 0.390625, 0.625, 1, 1, 1, 1, 1, 1, 1, 1, 1], "attackType": "POKEMON_TYPE_NORMAL"}},
+	{"templateId": "POKEMON_TYPE_FLYING", "typeEffective": {"attackScalar": [1, 1.6, 1, 1, 1, 0.625, 1.6, 1, 0.625, 1, 1, 1.6, 0.625, 1, 1, 1, 1, 1], "attackType": "POKEMON_TYPE_FLYING"}},
+	{"templateId": "V0221_MOVE_TACKLE_FAST", "moveSettings": {"movementId": "TACKLE_FAST", "pokemonType": "POKEMON_TYPE_NORMAL", "power": 5, "staminaLossScalar": 0.01, "durationMs": 500, "damageWindowStartMs": 300, "damageWindowEndMs": 500, "energyDelta": 5}},
+	{"templateId": "COMBAT_V0221_MOVE_TACKLE_FAST", "combatMove": {"uniqueId": "TACKLE_FAST", "type": "POKEMON_TYPE_NORMAL", "power": 3, "energyDelta": 3, "durationTurns": 0}},
+	{"templateId": "V0080_MOVE_TWISTER", "moveSettings": {"movementId": "TWISTER", "pokemonType": "POKEMON_TYPE_DRAGON", "power": 45, "criticalChance": 0.05, "staminaLossScalar": 0.04, "durationMs": 2800, "damageWindowStartMs": 950, "damageWindowEndMs": 2600, "energyDelta": -33}},
+	{"templateId": "V0387_MOVE_387", "moveSettings": {"movementId": 387, "pokemonType": "POKEMON_TYPE_FLYING", "power": 10, "energyDelta": 8, "durationMs": 1000}},
+	{"templateId": "FORMS_V0016_POKEMON_PIDGEY", "formSettings": {"pokemon": "PIDGEY", "forms": [{"form": "PIDGEY_NORMAL"}, {"form": "PIDGEY_SHADOW", "assetBundleValue": 11}]}},
+	{"templateId": "V0016_POKEMON_PIDGEY", "pokemonSettings": {"pokemonId": "PIDGEY", "type": "POKEMON_TYPE_NORMAL", "type2": "POKEMON_TYPE_FLYING", "stats": {"baseStamina": 120, "baseAttack": 85, "baseDefense": 73}, "quickMoves": ["TACKLE_FAST", 387], "cinematicMoves": ["TWISTER"], "encounter": {"baseCaptureRate": 0.5, "baseFleeRate": 0.2, "movementType": "MOVEMENT_FLYING"}, "familyId": "FAMILY_PIDGEY", "evolutionBranch": [{"evolution": "PIDGEOTTO", "candyCost": 12}]}},
+	{"templateId": "V0016_POKEMON_PIDGEY_NORMAL", "pokemonSettings": {"pokemonId": "PIDGEY", "form": "PIDGEY_NORMAL", "type": "POKEMON_TYPE_NORMAL", "type2": "POKEMON_TYPE_FLYING", "stats": {"baseStamina": 120, "baseAttack": 85, "baseDefense": 73}}},
+	{"templateId": "V0017_POKEMON_PIDGEOTTO", "pokemonSettings": {"pokemonId": "PIDGEOTTO", "type": "POKEMON_TYPE_NORMAL", "type2": "POKEMON_TYPE_FLYING", "stats": {"baseStamina": 160, "baseAttack": 117, "baseDefense": 105}, "familyId": "FAMILY_PIDGEY", "parentPokemonId": "PIDGEY"}},
+	{"templateId": "V0018_POKEMON_PIDGEOT", "pokemonSettings": {"pokemonId": "PIDGEOT", "type": "POKEMON_TYPE_NORMAL", "type2": "POKEMON_TYPE_FLYING", "stats": {"baseStamina": 195, "baseAttack": 166, "baseDefense": 154}, "familyId": "FAMILY_PIDGEY", "parentPokemonId": "PIDGEOTTO", "evolutionBranch": [{"temporaryEvolution": "TEMP_EVOLUTION_MEGA", "temporaryEvolutionEnergyCost": 200, "temporaryEvolutionEnergyCostSubsequent": 40}], "tempEvoOverrides": [{"tempEvoId": "TEMP_EVOLUTION_MEGA", "stats": {"baseStamina": 195, "baseAttack": 280, "baseDefense": 175}, "typeOverride1": "POKEMON_TYPE_NORMAL", "typeOverride2": "POKEMON_TYPE_FLYING"}, {"stats": {}}]}},
+	{"templateId": "V0122_POKEMON_MR_MIME", "pokemonSettings": {"pokemonId": "MR_MIME", "type": "POKEMON_TYPE_PSYCHIC", "type2": "POKEMON_TYPE_FAIRY", "stats": {"baseStamina": 120, "baseAttack": 192, "baseDefense": 205}}},
+	{"templateId": "PLAYER_LEVEL_SETTINGS", "playerLevel": {"cpMultiplier": [0.094, 0.16639787, 0.21573247, 0.25572005, 0.29024988, 0.3210876, 0.34921268, 0.3752356, 0.39956728, 0.42250001, 0.44310755, 0.46279839, 0.48168495, 0.49985844, 0.51739395, 0.53435433, 0.55079269, 0.56675452, 0.58227891, 0.59740001, 0.61215729, 0.62656713, 0.64065295, 0.65443563, 0.667934, 0.68116492, 0.69414365, 0.70688421, 0.71939909, 0.7317, 0.73776948, 0.74378943, 0.74976104, 0.75568551, 0.76156384, 0.76739717, 0.7731865, 0.77893275, 0.78463697, 0.79030001]}}`
+
+func TestParseGameMaster(t *testing.T) {
+	layouts := map[string]string{
+		"itemTemplates": `{"itemTemplates": [` + testGameMasterTemplates + `]}`,
+		"data":          "[" + strings.NewReplacer(`{"templateId": "`, `{"data": {"templateId": "`, "}},\n", "}}},\n").Replace(testGameMasterTemplates) + "}]",
+	}
+
+	for layout, gameMaster := range layouts {
+		d, err := ParseGameMaster(strings.NewReader(gameMaster))
+		if err != nil {
+			t.Error("For", layout, "unable to parse GAME_MASTER:", err)
+			continue
+		}
+
+		p, err := d.GetPokemon("pidgey")
+		if err != nil {
+			t.Error("For", layout, "expected Pidgey, got", err.Error())
+			continue
+		}
+		if p.Dex != 16 || p.MaxCP != 680 || p.Types.Print() != "Normal, Flying" {
+			t.Error("For", layout, "got", p.Dex, p.MaxCP, p.Types.Print())
+		}
+		if p.Moves.Fast.Print() != "Tackle, 387" || p.Moves.Fast[0].Power != 5 || p.Moves.Fast[0].Combat.Power != 3 {
+			t.Error("For", layout, "got fast moves", p.Moves.Fast.Print())
+		}
+		if p.Forms.Len() != 2 || p.Forms[0].ID != "PIDGEY" {
+			t.Error("For", layout, "got forms", p.Forms)
+		}
+		if cp := p.GetCP(20, 15, 15, 15); cp != 388 {
+			t.Error("For", layout, "expected level 20 CP 388, got", cp)
+		}
+
+		if p.Encounter.BaseCaptureRate != 0.5 || p.Encounter.MovementType == nil || p.Encounter.MovementType.Name != "Movement Flying" {
+			t.Error("For", layout, "got encounter", p.Encounter)
+		}
+		if steps := p.Evolutions(); len(steps) != 1 || steps[0].To.ID != "pidgeotto" || steps[0].CandyCost != 12 {
+			t.Error("For", layout, "expected evolution into Pidgeotto, got", steps)
+		}
+		if family, err := d.GetFamily("pidgeotto"); err != nil || len(family) != 3 {
+			t.Error("For", layout, "expected Pidgey family, got", family, err)
+		}
+
+		if mega, err := d.GetPokemon("mega-pidgeot"); err != nil || mega.Stats.BaseAttack != 280 || mega.Mega.EnergyCost != 200 {
+			t.Error("For", layout, "expected Mega Pidgeot, got", mega, err)
+		}
+		if p, err := d.GetPokemon("pidgeot"); err != nil || len(p.Evolutions()) != 0 || len(p.MegaEvolutions) != 1 {
+			t.Error("For", layout, "expected one mega evolution and no evolutions for Pidgeot, got", p, err)
+		}
+
+		if p, err := d.GetPokemon("mr-mime"); err != nil || p.Name != "Mr. Mime" {
+			t.Error("For", layout, "expected Mr. Mime, got", p, err)
+		}
+		if ty, err := d.GetType("flying"); err != nil || len(ty.Damage) != 18 || ty.Damage[1].ID != "POKEMON_TYPE_FIGHTING" || ty.Damage[1].Scalar != 1.6 {
+			t.Error("For", layout, "expected flying type, got", ty, err)
+		}
+	}
+}
+
+func TestParseGameMasterErrors(t *testing.T) {
+	for _, gameMaster := range []string{"", "[]", `{"itemTemplates": []}`, "{"} {
+		if _, err := ParseGameMaster(strings.NewReader(gameMaster)); err == nil {
+			t.Error("For", gameMaster, "expected error")
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
     "fmt"
     "github.com/bwmarrin/discordgo"
     "strconv"
-    "strings"
     "sync"
     "time"
 )
@@ -29,6 +28,7 @@ type IVCalculator struct {
 
 type IVCalculation struct {
     Session *discordgo.Session
+    Pokedex *Pokedex
     User *discordgo.User
     ChannelID string
     Pokemon *Pokemon
@@ -67,12 +67,19 @@ func StartIVCalculator(s *discordgo.Session) (*IVCalculator) {
 }
 
 func (calc *IVCalculator) Start(m *discordgo.MessageCreate) {
+    // Keep the same game data for the whole calculation, even if it is reloaded
     thisCalculation := &IVCalculation{
         Session: calc.Session,
         User: m.Author,
         ChannelID: m.ChannelID,
         Channel: make(chan interface{}),
     }
+    pokedex, err := DefaultPokedex()
+    if err != nil {
+        thisCalculation.PrintToDiscord("Unable to load the game data: " + err.Error())
+        return
+    }
+    thisCalculation.Pokedex = pokedex
     calc.lock.Lock()
     calc.RunningCalculations[m.Author.ID] = thisCalculation
     calc.lock.Unlock()
@@ -148,8 +155,8 @@ func (ivCalc *IVCalculation) GetResponse(m string) {
     
     switch ivCalc.Status {
     case status_expecting_pokemon:
-        if p, ok := pokemonMap[strings.ToLower(m)]; ok {
-            ivCalc.Pokemon = &p
+        if p, err := ivCalc.Pokedex.GetPokemon(m); err == nil {
+            ivCalc.Pokemon = p
             ivCalc.Status++
             ivCalc.AskQuestion()
         } else {
@@ -0,0 +1,183 @@
+package pogo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors
+var (
+	ERR_NO_IVS = errors.New("No IVs match the readings.")
+)
+
+// Reading is one look at a pokemon in an IVSession
+type Reading struct {
+	// Pokemon is the species at the time of the reading, which changes
+	// when the pokemon is evolved
+	Pokemon *Pokemon
+	CP      int
+	HP      int
+	// Stardust is the cost to power up shown on the reading, or 0 if unknown
+	Stardust int
+	// PowerUps is how many times the pokemon was powered up since the last
+	// reading. When 0, the same species is taken to have been powered up
+	// an unknown number of times and an evolution not at all.
+	PowerUps int
+}
+
+// IVSession narrows down the IVs of a single pokemon over several readings,
+// such as before and after powering it up or evolving it
+type IVSession struct {
+	Appraisal Appraisal
+	Lucky     bool
+
+	readings   []Reading
+	candidates []IVStat
+}
+
+// NewIVSession starts an IV session, with an optional appraisal that every
+// candidate must match
+func NewIVSession(appraisal Appraisal, lucky bool) *IVSession {
+	return &IVSession{Appraisal: appraisal, Lucky: lucky}
+}
+
+// AddReading narrows the candidate IVs with another reading. If no IVs match
+// every reading, ERR_NO_IVS is returned and the reading is not added.
+func (s *IVSession) AddReading(r Reading) error {
+	if r.Pokemon == nil {
+		return ERR_NOT_FOUND
+	}
+
+	matches, err := s.matchReading(r)
+	if err != nil {
+		return err
+	}
+	if len(s.readings) > 0 {
+		last := s.readings[len(s.readings)-1]
+		evolved := last.Pokemon.ID != r.Pokemon.ID
+
+		narrowed := []IVStat{}
+		for _, m := range matches {
+			for _, c := range s.candidates {
+				if c.Attack != m.Attack || c.Defense != m.Defense || c.Stamina != m.Stamina {
+					continue
+				}
+				if r.PowerUps > 0 && m.Level == c.Level+0.5*float64(r.PowerUps) ||
+					r.PowerUps == 0 && evolved && m.Level == c.Level ||
+					r.PowerUps == 0 && !evolved && m.Level >= c.Level {
+					narrowed = append(narrowed, m)
+					break
+				}
+			}
+		}
+		matches = narrowed
+	}
+
+	if len(matches) == 0 {
+		return ERR_NO_IVS
+	}
+	s.readings = append(s.readings, r)
+	s.candidates = matches
+	return nil
+}
+
+// matchReading returns every level and IV spread that gives the reading
+func (s *IVSession) matchReading(r Reading) ([]IVStat, error) {
+	p := r.Pokemon
+	dex, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	matches := []IVStat{}
+	for _, c := range dex.getCPCandidates(p, r.CP, r.HP) {
+		l, a, d, st := c.level(), int(c.attack), int(c.defense), int(c.stamina)
+		if r.Stardust != 0 {
+			dust := p.GetPowerUpCost(l).Stardust
+			if s.Lucky {
+				dust = (dust + 1) / 2
+			}
+			if dust != r.Stardust {
+				continue
+			}
+		}
+		if !s.Appraisal.Matches(a, d, st) {
+			continue
+		}
+		matches = append(matches, IVStat{
+			Level:    l,
+			Attack:   a,
+			Defense:  d,
+			Stamina:  st,
+			CP:       r.CP,
+			HP:       r.HP,
+			Stardust: r.Stardust,
+			Percent:  round(float64((a+d+st)*100) / float64(45)),
+		})
+	}
+	return matches, nil
+}
+
+// IVResult is an IV spread still possible after every reading
+type IVResult struct {
+	Attack  int `json:"attack"`
+	Defense int `json:"defense"`
+	Stamina int `json:"stamina"`
+	Percent int `json:"percent"`
+	// Levels are the possible levels at the latest reading
+	Levels []float64 `json:"levels"`
+	// Confidence is the chance these are the IVs, treating every remaining
+	// level and IV candidate as equally likely
+	Confidence float64 `json:"confidence"`
+}
+
+// Results returns the IV spreads that match every reading, best first
+func (s *IVSession) Results() []IVResult {
+	results := []IVResult{}
+	index := map[[3]int]int{}
+	for _, c := range SortChart(append([]IVStat{}, s.candidates...)) {
+		key := [3]int{c.Attack, c.Defense, c.Stamina}
+		i, ok := index[key]
+		if !ok {
+			i = len(results)
+			index[key] = i
+			results = append(results, IVResult{Attack: c.Attack, Defense: c.Defense, Stamina: c.Stamina, Percent: c.Percent})
+		}
+		results[i].Levels = append(results[i].Levels, c.Level)
+		results[i].Confidence += 1 / float64(len(s.candidates))
+	}
+	return results
+}
+
+// Confidence returns the chance of the most likely IV spread, which is 1
+// once the readings leave a single spread
+func (s *IVSession) Confidence() float64 {
+	best := 0.0
+	for _, r := range s.Results() {
+		if r.Confidence > best {
+			best = r.Confidence
+		}
+	}
+	return best
+}
+
+// Candidates returns every level and IV spread that matches the readings,
+// with the level at the latest reading
+func (s *IVSession) Candidates() []IVStat {
+	return append([]IVStat{}, s.candidates...)
+}
+
+// Readings returns the number of readings added so far
+func (s *IVSession) Readings() int {
+	return len(s.readings)
+}
+
+func (s *IVSession) Print() string {
+	str := "| At | Df | St | % | Chance\n"
+	str += "|----|----|----|---|-------\n"
+	rows := []string{}
+	for _, r := range s.Results() {
+		rows = append(rows, fmt.Sprintf("| %2d | %2d | %2d |%3d| %5.1f%%", r.Attack, r.Defense, r.Stamina, r.Percent, r.Confidence*100))
+	}
+	return str + strings.Join(rows, "\n")
+}
@@ -0,0 +1,92 @@
+package pogo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestIVSession(t *testing.T) {
+	pidgey, err := GetPokemon("pidgey")
+	if err != nil {
+		t.Fatal("Unable to get pidgey:", err)
+	}
+	pidgeotto, _ := GetPokemon("pidgeotto")
+
+	reading := func(p *Pokemon, level float64, powerUps int) Reading {
+		return Reading{
+			Pokemon:  p,
+			CP:       p.GetCP(level, 10, 12, 13),
+			HP:       p.GetHP(level, 13),
+			Stardust: p.GetPowerUpCost(level).Stardust,
+			PowerUps: powerUps,
+		}
+	}
+
+	s := NewIVSession(Appraisal{}, false)
+	counts := []int{}
+	for _, r := range []Reading{
+		reading(pidgey, 10, 0),
+		reading(pidgey, 10.5, 1),
+		reading(pidgey, 12, 0),
+		reading(pidgeotto, 12, 0),
+		reading(pidgeotto, 14, 4),
+	} {
+		if err := s.AddReading(r); err != nil {
+			t.Fatal("Unable to add reading", s.Readings()+1, err)
+		}
+		counts = append(counts, len(s.Results()))
+	}
+
+	// 29 spreads at level 10 or 10.5 give the first reading. Powering up
+	// leaves the 6 with 13 stamina at 10.5, and evolving the 2 that give
+	// the same pidgeotto CP.
+	if fmt.Sprint(counts) != "[29 6 6 2 2]" {
+		t.Error("Expected the readings to narrow the IVs to [29 6 6 2 2], got", counts)
+	}
+	if spreads := resultSpreads(s); spreads != "10/12/13@14 11/10/13@14" {
+		t.Error("Expected 10/12/13 and 11/10/13 at level 14, got", spreads)
+	}
+
+	total := 0.0
+	for _, r := range s.Results() {
+		total += r.Confidence
+	}
+	if total < 0.999 || total > 1.001 || s.Confidence() <= 0 {
+		t.Error("Expected confidences to add up to 1, got", total, s.Confidence())
+	}
+
+	// A reading that contradicts the others is rejected
+	bad := reading(pidgeotto, 14, 0)
+	bad.CP = 10000
+	if err := s.AddReading(bad); err != ERR_NO_IVS || s.Readings() != 5 {
+		t.Error("Expected contradicting reading to be rejected, got", err)
+	}
+
+	// An appraisal of defense as the best stat leaves 6 spreads, and the
+	// power up only 9/14/13
+	s = NewIVSession(Appraisal{Best: "d"}, false)
+	if err := s.AddReading(reading(pidgey, 10, 0)); err != nil {
+		t.Fatal("Unable to add reading:", err)
+	}
+	if spreads := resultSpreads(s); spreads != "5/14/11@10.5 5/15/10@10.5 6/13/10@10.5 7/11/10@10.5 8/15/14@10 9/14/13@10" {
+		t.Error("Expected 6 spreads with defense best, got", spreads)
+	}
+	if err := s.AddReading(reading(pidgey, 10.5, 1)); err != nil {
+		t.Fatal("Unable to add reading:", err)
+	}
+	if spreads := resultSpreads(s); spreads != "9/14/13@10.5" {
+		t.Error("Expected only 9/14/13 at level 10.5, got", spreads)
+	}
+}
+
+// resultSpreads returns the sorted IVs and latest level of each result
+func resultSpreads(s *IVSession) string {
+	spreads := []string{}
+	for _, r := range s.Results() {
+		spreads = append(spreads, fmt.Sprintf("%d/%d/%d@%v", r.Attack, r.Defense, r.Stamina, r.Levels[len(r.Levels)-1]))
+	}
+	sort.Strings(spreads)
+	return strings.Join(spreads, " ")
+}
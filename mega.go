@@ -0,0 +1,119 @@
+package pogo
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors
+var (
+	ERR_NOT_MEGA = errors.New("Pokemon is not a mega pokemon.")
+)
+
+// While a mega pokemon is in a raid party it boosts the attack of the whole
+// party, more so for moves sharing one of its types
+const (
+	MEGA_BOOST           = 1.1
+	MEGA_BOOST_SAME_TYPE = 1.3
+)
+
+// MegaEvolution is a temporary evolution of a pokemon, such as
+// TEMP_EVOLUTION_MEGA_X, with its own stats and types
+type MegaEvolution struct {
+	ID      string       `json:"id"`
+	Pokemon string       `json:"pokemon"` // id of the pokemon that mega evolves
+	Stats   PokemonStats `json:"stats"`
+	Types   TypeList     `json:"types"`
+
+	// EnergyCost is the mega energy needed the first time, and
+	// EnergyCostSubsequent every time after that
+	EnergyCost           int `json:"energyCost"`
+	EnergyCostSubsequent int `json:"energyCostSubsequent"`
+}
+
+// megaID returns the id of the mega form, such as charizard-mega-x
+func (m *MegaEvolution) megaID() string {
+	return m.Pokemon + "-" + pokemonKey(strings.TrimPrefix(m.ID, "TEMP_EVOLUTION_"))
+}
+
+// megaPokemon returns the mega form of a pokemon. Types and stats not given
+// for the mega evolution are kept from the pokemon.
+func (m *MegaEvolution) megaPokemon(d *Pokedex, poke Pokemon) Pokemon {
+	mega := poke
+	mega.ID = m.megaID()
+	mega.Name = poke.Name + " " + gameMasterName(strings.TrimPrefix(m.ID, "TEMP_EVOLUTION_"))
+	mega.Forms = nil
+	mega.Evolution = Evolution{}
+	mega.MegaEvolutions = nil
+	mega.Mega = m
+	if m.Stats != (PokemonStats{}) {
+		mega.Stats = m.Stats
+	}
+	if len(m.Types) > 0 {
+		mega.Types = m.Types
+	}
+	mega.MaxCP = d.getMaxCP(mega.Stats)
+	return mega
+}
+
+// IsMega returns true if the pokemon is a mega evolved form
+func (p *Pokemon) IsMega() bool {
+	return p.Mega != nil
+}
+
+// GetMegas returns the mega forms the pokemon can mega evolve into. CP and
+// HP at a level come from GetCP and GetHP on the mega form as usual. Megas
+// are only known when the data has them, such as a GAME_MASTER loaded with
+// ParseGameMaster. The bundled json has none, so this is empty for the
+// embedded Pokedex.
+func (p *Pokemon) GetMegas() []Pokemon {
+	megas := []Pokemon{}
+	d, err := p.getPokedex()
+	if err != nil {
+		return megas
+	}
+	for _, m := range p.MegaEvolutions {
+		if mega, err := d.GetPokemon(m.megaID()); err == nil {
+			megas = append(megas, *mega)
+		}
+	}
+	return megas
+}
+
+// GetMegaBase returns the pokemon a mega form mega evolves from
+func (p *Pokemon) GetMegaBase() (*Pokemon, error) {
+	if !p.IsMega() {
+		return nil, ERR_NOT_MEGA
+	}
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	return d.GetPokemon(p.Mega.Pokemon)
+}
+
+// GetMegaEnergyCost returns the mega energy it takes to mega evolve into
+// this form, which is less once it has been mega evolved before
+func (p *Pokemon) GetMegaEnergyCost(megaEvolvedBefore bool) (int, error) {
+	if !p.IsMega() {
+		return 0, ERR_NOT_MEGA
+	}
+	if megaEvolvedBefore {
+		return p.Mega.EnergyCostSubsequent, nil
+	}
+	return p.Mega.EnergyCost, nil
+}
+
+// GetMegaBoost returns the attack boost a mega pokemon gives a move used by
+// anyone in its party, or 1 if the pokemon isn't a mega
+func (p *Pokemon) GetMegaBoost(move *Move) float64 {
+	if !p.IsMega() {
+		return 1
+	}
+	for _, t := range p.Types {
+		if t.ID == move.Type.ID {
+			return MEGA_BOOST_SAME_TYPE
+		}
+	}
+	return MEGA_BOOST
+}
@@ -0,0 +1,98 @@
+package pogo
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMegaEvolution(t *testing.T) {
+	fsys := testStoreFS("Pidgeot")
+	fsys["type.json"] = &fstest.MapFile{Data: []byte(`[
+		{"id":"POKEMON_TYPE_NORMAL","name":"Normal","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1}]},
+		{"id":"POKEMON_TYPE_FLYING","name":"Flying","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1}]},
+		{"id":"POKEMON_TYPE_FIRE","name":"Fire","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1}]}]`)}
+	fsys["pokemon.json"] = &fstest.MapFile{Data: []byte(`[{"id":"PIDGEOT","name":"Pidgeot","dex":18,
+		"types":[{"id":"POKEMON_TYPE_NORMAL","name":"Normal"},{"id":"POKEMON_TYPE_FLYING","name":"Flying"}],
+		"stats":{"baseAttack":166,"baseDefense":154,"baseStamina":195},
+		"forms":[{"id":"PIDGEOT_SHADOW","name":"Pidgeot Shadow"}],
+		"megaEvolutions":[{"id":"TEMP_EVOLUTION_MEGA","stats":{"baseAttack":280,"baseDefense":175,"baseStamina":195},"energyCost":200,"energyCostSubsequent":40}]}]`)}
+	d, err := NewPokedex(fsys)
+	if err != nil {
+		t.Fatal("Unable to load pokedex:", err)
+	}
+
+	p, err := d.GetPokemon("pidgeot")
+	if err != nil {
+		t.Fatal("Unable to get pidgeot:", err)
+	}
+	megas := p.GetMegas()
+	if len(megas) != 1 {
+		t.Fatal("Expected one mega form, got", megas)
+	}
+	mega := megas[0]
+	if mega.ID != "pidgeot-mega" || mega.Name != "Pidgeot Mega" || !mega.IsMega() || p.IsMega() {
+		t.Error("Expected Pidgeot Mega, got", mega.ID, mega.Name)
+	}
+	if alias, err := d.GetPokemon("mega-pidgeot"); err != nil || alias.ID != mega.ID {
+		t.Error("Expected mega-pidgeot alias, got", alias, err)
+	}
+	if mega.Types.Print() != "Normal, Flying" {
+		t.Error("Expected the base types to be kept, got", mega.Types.Print())
+	}
+	if cp := mega.GetCP(20, 15, 15, 15); cp <= p.GetCP(20, 15, 15, 15) || mega.GetHP(20, 15) != p.GetHP(20, 15) {
+		t.Error("Expected higher mega CP and the same HP, got", cp, mega.GetHP(20, 15))
+	}
+	if mega.MaxCP != mega.GetCP(40, 15, 15, 15) {
+		t.Error("Expected mega max CP of", mega.GetCP(40, 15, 15, 15), "got", mega.MaxCP)
+	}
+	if base, err := mega.GetMegaBase(); err != nil || base.ID != "pidgeot" {
+		t.Error("Expected pidgeot as the base, got", base, err)
+	}
+	if _, err := p.GetMegaBase(); err != ERR_NOT_MEGA {
+		t.Error("Expected not mega error, got", err)
+	}
+
+	if cost, err := mega.GetMegaEnergyCost(false); err != nil || cost != 200 {
+		t.Error("Expected 200 energy, got", cost, err)
+	}
+	if cost, err := mega.GetMegaEnergyCost(true); err != nil || cost != 40 {
+		t.Error("Expected 40 energy, got", cost, err)
+	}
+
+	// Max CPs use the game data's own CP multipliers
+	levels := []string{}
+	for l := 1; l <= 40; l++ {
+		levels = append(levels, fmt.Sprintf(`{"level":%d,"cpMultiplier":%v}`, l, 0.1+float64(l)/100))
+	}
+	fsys["level.json"] = &fstest.MapFile{Data: []byte("[" + strings.Join(levels, ",") + "]")}
+	leveled, err := NewPokedex(fsys)
+	if err != nil {
+		t.Fatal("Unable to load pokedex with levels:", err)
+	}
+	if m, err := leveled.GetPokemon("pidgeot-mega"); err != nil || m.MaxCP != m.GetCP(40, 15, 15, 15) || m.MaxCP == mega.MaxCP {
+		t.Error("Expected mega max CP from the level file, got", m, err)
+	}
+
+	if shadow, err := d.GetPokemon("pidgeot-shadow"); err != nil || len(shadow.GetMegas()) != 0 {
+		t.Error("Expected no mega forms for shadow pidgeot, got", shadow, err)
+	}
+
+	flying := &Move{Power: 10, Type: PokemonType{ID: "POKEMON_TYPE_FLYING"}}
+	fire := &Move{Power: 10, Type: PokemonType{ID: "POKEMON_TYPE_FIRE"}}
+	if boost := mega.GetMegaBoost(flying); boost != MEGA_BOOST_SAME_TYPE {
+		t.Error("Expected same type boost, got", boost)
+	}
+	if boost := mega.GetMegaBoost(fire); boost != MEGA_BOOST {
+		t.Error("Expected mega boost, got", boost)
+	}
+	if boost := p.GetMegaBoost(flying); boost != 1 {
+		t.Error("Expected no boost, got", boost)
+	}
+
+	// 0.5 * 10 power * 200/100 * 1.2 STAB * 1.3 boost, plus one
+	if damage := GetBoostedDamage(flying, p, 200, p, 100, mega.GetMegaBoost(flying)); damage != 16 {
+		t.Error("Expected 16 damage, got", damage)
+	}
+}
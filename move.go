@@ -1,19 +1,108 @@
 package pogo
 
 import (
+	"errors"
 	"strings"
 )
 
+var (
+	ERR_MOVE_NOT_FOUND = errors.New("Move not found.")
+)
+
 type Moves struct {
 	Fast   MoveList `json:"quickMoves"`
 	Charge MoveList `json:"cinematicMoves"`
 }
 
-type MoveList []*PokemonMove
+type MoveList []*Move
+
+// PokemonMove is the move reference stored on a pokemon, which is resolved
+// to the full Move when the Pokedex is loaded
+type PokemonMove = Move
+
+// Move is a resource representing a single fast or charge move
+type Move struct {
+	ID                  string      `json:"id"`
+	Name                string      `json:"name"`
+	Type                PokemonType `json:"pokemonType"`
+	Power               float64     `json:"power"`
+	EnergyDelta         int         `json:"energyDelta"`
+	DurationMs          int         `json:"durationMs"`
+	DamageWindowStartMs int         `json:"damageWindowStartMs"`
+	DamageWindowEndMs   int         `json:"damageWindowEndMs"`
+	CriticalChance      float64     `json:"criticalChance"`
+	StaminaLossScalar   float64     `json:"staminaLossScalar"`
+	Combat              CombatMove  `json:"combat"`
+}
+
+// CombatMove holds the trainer battle stats of a move. They are only
+// available when the Pokedex was loaded from a GAME_MASTER file.
+type CombatMove struct {
+	Power         float64 `json:"power"`
+	EnergyDelta   int     `json:"energyDelta"`
+	DurationTurns int     `json:"durationTurns"`
+}
+
+// GetMove returns a Move resource from the default Pokedex
+func GetMove(moveName string) (*Move, error) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		return nil, err
+	}
+	return d.GetMove(moveName)
+}
+
+// GetMove returns a Move resource by name or id, ignoring case. A name
+// shared by more than one move finds the first of them, so look those up by id.
+func (d *Pokedex) GetMove(moveName string) (*Move, error) {
+	if id, ok := d.moveToID[moveKey(moveName)]; ok {
+		m := *d.moveMap[id]
+		return &m, nil
+	}
+	return nil, ERR_MOVE_NOT_FOUND
+}
+
+// IsFast returns true if the move is a fast move
+func (m *Move) IsFast() bool {
+	return strings.HasSuffix(m.ID, "_FAST")
+}
+
+// moveKey normalizes a move name or id for lookups, so "Hydro Pump",
+// "hydro-pump" and "HYDRO_PUMP" are all the same move
+func moveKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer(" ", "-", "_", "-").Replace(name)
+}
 
-type PokemonMove struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+// clone returns copies of the moves. The Pokedex hands out copies, like
+// GetMove, so changing a pokemon's moves doesn't change the shared Pokedex.
+func (m Moves) clone() Moves {
+	return Moves{Fast: m.Fast.clone(), Charge: m.Charge.clone()}
+}
+
+func (moveList MoveList) clone() MoveList {
+	if moveList == nil {
+		return nil
+	}
+	cloned := make(MoveList, 0, len(moveList))
+	for _, m := range moveList {
+		move := *m
+		cloned = append(cloned, &move)
+	}
+	return cloned
+}
+
+// resolveMoves replaces the move references on a pokemon with the full
+// moves from the Pokedex, keeping any that are unknown as they are
+func (d *Pokedex) resolveMoves(moveList MoveList) MoveList {
+	resolved := make(MoveList, 0, len(moveList))
+	for _, m := range moveList {
+		if move, ok := d.moveMap[m.ID]; ok {
+			m = move
+		}
+		resolved = append(resolved, m)
+	}
+	return resolved
 }
 
 func (moveList MoveList) Print() string {
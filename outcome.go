@@ -0,0 +1,165 @@
+package pogo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Errors
+var (
+	ERR_NOT_SHADOW   = errors.New("Pokemon is not a shadow pokemon.")
+	ERR_CANNOT_TRADE = errors.New("Shadow pokemon can't be traded.")
+)
+
+// TOP_PVP_RANK is the rank an IV spread needs to count as a top PvP spread
+const TOP_PVP_RANK = 100
+
+// IVChance is a possible IV spread and level and its chance
+type IVChance struct {
+	IVStat
+	Chance float64 `json:"chance"`
+}
+
+// LeagueChance is the chance of a top PvP rank in a league
+type LeagueChance struct {
+	CPCap  int     `json:"cpCap"`
+	Chance float64 `json:"chance"`
+}
+
+// IVOutcome is every IV spread a pokemon could end up with, such as after
+// purifying or trading it
+type IVOutcome struct {
+	Pokemon *Pokemon       `json:"pokemon"`
+	Spreads []IVChance     `json:"spreads"`
+	MinCP   int            `json:"minCP"`
+	MaxCP   int            `json:"maxCP"`
+	Hundo   float64        `json:"hundo"`
+	TopRank []LeagueChance `json:"topRank"`
+}
+
+// PurifyOutcome returns the IVs a shadow pokemon will have once purified,
+// for one or more candidate levels and IVs such as the results of GetIV.
+// Each candidate is taken to be equally likely.
+func (p *Pokemon) PurifyOutcome(candidates ...IVStat) (*IVOutcome, error) {
+	if !p.IsShadow() {
+		return nil, ERR_NOT_SHADOW
+	}
+	if len(candidates) == 0 {
+		return nil, ERR_NO_IVS
+	}
+	purified, err := p.GetPurified()
+	if err != nil {
+		return nil, err
+	}
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+
+	chances := map[IVStat]float64{}
+	for _, c := range candidates {
+		if d.getMultiplier(c.Level) == 0 {
+			return nil, ERR_INVALID_LEVEL
+		}
+		level, a, df, s := PurifyIV(c.Level, c.Attack, c.Defense, c.Stamina)
+		chances[IVStat{Level: level, Attack: a, Defense: df, Stamina: s}] += 1 / float64(len(candidates))
+	}
+	return purified.newIVOutcome(chances), nil
+}
+
+// TradeOutcome returns the IVs a pokemon at a level could have after it is
+// traded. Every IV from the context's floor, such as that of CONTEXT_TRADE_BEST
+// or CONTEXT_TRADE_LUCKY, up to 15 is equally likely, and the level is kept.
+func (p *Pokemon) TradeOutcome(level float64, ctx EncounterContext) (*IVOutcome, error) {
+	if p.IsShadow() {
+		return nil, ERR_CANNOT_TRADE
+	}
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	if d.getMultiplier(level) == 0 {
+		return nil, ERR_INVALID_LEVEL
+	}
+
+	ivs := ctx.possibleIVs()
+	total := float64(len(ivs) * len(ivs) * len(ivs))
+	chances := map[IVStat]float64{}
+	for _, a := range ivs {
+		for _, df := range ivs {
+			for _, s := range ivs {
+				chances[IVStat{Level: level, Attack: a, Defense: df, Stamina: s}] = 1 / total
+			}
+		}
+	}
+	return p.newIVOutcome(chances), nil
+}
+
+// newIVOutcome fills in the CP, HP and percent of each spread and works out
+// the CP range and the chance of a hundo or a top PvP rank
+func (p *Pokemon) newIVOutcome(chances map[IVStat]float64) *IVOutcome {
+	outcome := &IVOutcome{Pokemon: p, Spreads: []IVChance{}}
+	for iv, chance := range chances {
+		iv.CP = p.GetCP(iv.Level, iv.Attack, iv.Defense, iv.Stamina)
+		iv.HP = p.GetHP(iv.Level, iv.Stamina)
+		iv.Percent = round(float64((iv.Attack+iv.Defense+iv.Stamina)*100) / float64(45))
+		outcome.Spreads = append(outcome.Spreads, IVChance{IVStat: iv, Chance: chance})
+
+		if len(outcome.Spreads) == 1 || iv.CP < outcome.MinCP {
+			outcome.MinCP = iv.CP
+		}
+		if iv.CP > outcome.MaxCP {
+			outcome.MaxCP = iv.CP
+		}
+		if iv.Attack == 15 && iv.Defense == 15 && iv.Stamina == 15 {
+			outcome.Hundo += chance
+		}
+	}
+
+	sort.Slice(outcome.Spreads, func(i, j int) bool {
+		s1, s2 := outcome.Spreads[i], outcome.Spreads[j]
+		if s1.Percent != s2.Percent {
+			return s1.Percent > s2.Percent
+		}
+		if s1.Attack != s2.Attack {
+			return s1.Attack > s2.Attack
+		}
+		if s1.Defense != s2.Defense {
+			return s1.Defense > s2.Defense
+		}
+		if s1.Stamina != s2.Stamina {
+			return s1.Stamina > s2.Stamina
+		}
+		return s1.Level > s2.Level
+	})
+
+	for _, cpCap := range PvPLeagues {
+		league := LeagueChance{CPCap: cpCap}
+		if ranking, err := p.GetPvPRanking(cpCap, 0); err == nil {
+			for _, spread := range outcome.Spreads {
+				if rank, err := ranking.Rank(spread.Attack, spread.Defense, spread.Stamina); err == nil && rank.Rank <= TOP_PVP_RANK {
+					league.Chance += spread.Chance
+				}
+			}
+		}
+		outcome.TopRank = append(outcome.TopRank, league)
+	}
+	return outcome
+}
+
+func (o *IVOutcome) Print() string {
+	str := fmt.Sprintf("%s: CP %d - %d, %.2f%% hundo", o.Pokemon.Name, o.MinCP, o.MaxCP, o.Hundo*100)
+	for _, league := range o.TopRank {
+		str += fmt.Sprintf(", %.2f%% top %d in %d", league.Chance*100, TOP_PVP_RANK, league.CPCap)
+	}
+	rows := []string{}
+	for i, s := range o.Spreads {
+		if i >= 30 {
+			break
+		}
+		rows = append(rows, fmt.Sprintf("%s %5d %5.2f%%", s.PrintIVRow(), s.CP, s.Chance*100))
+	}
+	return str + "\n" + strings.Join(rows, "\n")
+}
@@ -0,0 +1,94 @@
+package pogo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTradeOutcome(t *testing.T) {
+	azumarill, err := GetPokemon("azumarill")
+	if err != nil {
+		t.Fatal("Unable to get azumarill:", err)
+	}
+
+	outcome, err := azumarill.TradeOutcome(20, CONTEXT_TRADE_LUCKY.Context())
+	if err != nil {
+		t.Fatal("Unable to get lucky trade outcome:", err)
+	}
+	if len(outcome.Spreads) != 64 || math.Abs(outcome.Hundo-1.0/64) > 1e-9 {
+		t.Error("Expected 64 lucky spreads and a 1/64 hundo chance, got", len(outcome.Spreads), outcome.Hundo)
+	}
+	if s := outcome.Spreads[0]; s.Attack != 15 || s.Defense != 15 || s.Stamina != 15 || s.Percent != 100 {
+		t.Error("Expected 15/15/15 first, got", s)
+	}
+	if outcome.MinCP != azumarill.GetCP(20, 12, 12, 12) || outcome.MaxCP != azumarill.GetCP(20, 15, 15, 15) {
+		t.Error("Expected CP range at level 20, got", outcome.MinCP, outcome.MaxCP)
+	}
+
+	outcome, err = azumarill.TradeOutcome(20, CONTEXT_TRADE.Context())
+	if err != nil {
+		t.Fatal("Unable to get trade outcome:", err)
+	}
+	ranking, err := azumarill.GetPvPRanking(GREAT_LEAGUE, 0)
+	if err != nil {
+		t.Fatal("Unable to rank azumarill:", err)
+	}
+	top := 0
+	for _, r := range ranking.Ranks {
+		if r.Rank <= TOP_PVP_RANK {
+			top++
+		}
+	}
+	if len(outcome.TopRank) != len(PvPLeagues) || outcome.TopRank[1].CPCap != GREAT_LEAGUE {
+		t.Fatal("Expected a chance for each league, got", outcome.TopRank)
+	}
+	if chance := outcome.TopRank[1].Chance; math.Abs(chance-float64(top)/4096) > 1e-9 {
+		t.Error("Expected top great league chance of", top, "/ 4096, got", chance)
+	}
+
+	shadow, _ := GetPokemon("bulbasaur-shadow")
+	if _, err := shadow.TradeOutcome(20, CONTEXT_TRADE.Context()); err != ERR_CANNOT_TRADE {
+		t.Error("Expected shadow trade error, got", err)
+	}
+	if _, err := azumarill.TradeOutcome(60, CONTEXT_TRADE.Context()); err != ERR_INVALID_LEVEL {
+		t.Error("Expected invalid level error, got", err)
+	}
+}
+
+func TestPurifyOutcome(t *testing.T) {
+	shadow, err := GetPokemon("bulbasaur-shadow")
+	if err != nil {
+		t.Fatal("Unable to get shadow pokemon:", err)
+	}
+
+	// Both candidates purify to a hundo at level 25
+	outcome, err := shadow.PurifyOutcome(
+		IVStat{Level: 8, Attack: 13, Defense: 14, Stamina: 15},
+		IVStat{Level: 13, Attack: 15, Defense: 15, Stamina: 13},
+	)
+	if err != nil {
+		t.Fatal("Unable to get purify outcome:", err)
+	}
+	if !outcome.Pokemon.IsPurified() || len(outcome.Spreads) != 1 || outcome.Hundo != 1 {
+		t.Fatal("Expected a purified hundo, got", outcome.Pokemon.ID, outcome.Spreads)
+	}
+	if s := outcome.Spreads[0]; s.Level != PURIFIED_LEVEL || s.CP != outcome.Pokemon.GetCP(25, 15, 15, 15) {
+		t.Error("Expected level 25 hundo, got", s)
+	}
+
+	outcome, err = shadow.PurifyOutcome(
+		IVStat{Level: 30, Attack: 0, Defense: 7, Stamina: 14},
+		IVStat{Level: 30, Attack: 15, Defense: 13, Stamina: 10},
+	)
+	if err != nil || len(outcome.Spreads) != 2 || outcome.Spreads[0].Chance != 0.5 || outcome.Hundo != 0 {
+		t.Error("Expected two even spreads, got", outcome, err)
+	}
+
+	normal, _ := GetPokemon("bulbasaur")
+	if _, err := normal.PurifyOutcome(IVStat{Level: 20}); err != ERR_NOT_SHADOW {
+		t.Error("Expected not shadow error, got", err)
+	}
+	if _, err := shadow.PurifyOutcome(); err != ERR_NO_IVS {
+		t.Error("Expected no IVs error, got", err)
+	}
+}
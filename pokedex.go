@@ -0,0 +1,397 @@
+package pogo
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Errors
+var (
+	ERR_NO_POKEMON = errors.New("No pokemon found in data.")
+	ERR_NO_TYPES   = errors.New("No types found in data.")
+	ERR_NO_MOVES   = errors.New("No moves found in data.")
+	ERR_LEVELS     = errors.New("Levels must be every whole level from 1.")
+)
+
+// Pokedex is a loaded set of game data: pokemon, their dex numbers, types and
+// moves. Every Pokedex is independent, so several can be used side by side.
+//
+// A pokemon built by hand rather than loaded uses the default Pokedex. If
+// that can't be loaded, its methods that return errors return the load
+// error, and the rest fall back to the built in CP multipliers, neutral type
+// effectiveness and no related pokemon.
+type Pokedex struct {
+	pokemonList []Pokemon
+	pokemonMap  map[string]Pokemon
+	dexMap      map[int]string
+	typeMap     map[string]Type
+	typeToID    map[string]string
+	moveMap     map[string]*Move
+	moveToID    map[string]string
+	familyMap   map[string][]string
+	typeMatrix  TypeMatrix
+
+	// multiplierMap holds the CP multipliers from the game data, when it has them
+	multiplierMap map[float64]float64
+
+	// cpIndexes are the reverse CP indexes built with BuildCPIndex, up to
+	// MAX_CP_INDEXES of them. indexUses counts lookups, to find the least
+	// recently used.
+	cpIndexes map[PokemonStats]*cpIndex
+	indexUses uint64
+	indexLock sync.Mutex
+}
+
+// LoadOptions controls where Load reads the game data from. When none of
+// GameMaster, FS or Dir is set, the json files embedded in the package are used.
+type LoadOptions struct {
+	// GameMaster is the path of a raw GAME_MASTER json file.
+	GameMaster string
+	// FS holds pokemon.json, move.json and type.json at its root.
+	FS fs.FS
+	// Dir is a directory holding pokemon.json, move.json and type.json.
+	Dir string
+}
+
+// embeddedJSON is the bundled copy of the json directory
+//
+//go:embed json/*.json
+var embeddedJSON embed.FS
+
+var (
+	defaultLock  sync.Mutex
+	defaultStore *Store
+)
+
+// Load reads the game data described by opts and returns a new Pokedex
+func Load(opts LoadOptions) (*Pokedex, error) {
+	if opts.GameMaster != "" {
+		file, err := os.Open(opts.GameMaster)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return ParseGameMaster(file)
+	}
+	if opts.FS != nil {
+		return NewPokedex(opts.FS)
+	}
+	if opts.Dir != "" {
+		return NewPokedex(os.DirFS(opts.Dir))
+	}
+	return NewPokedex(EmbeddedFS())
+}
+
+// EmbeddedFS returns the json files bundled with the package
+func EmbeddedFS() fs.FS {
+	fsys, err := fs.Sub(embeddedJSON, "json")
+	if err != nil {
+		panic(err)
+	}
+	return fsys
+}
+
+// NewPokedex builds a Pokedex from the json files found in fsys
+func NewPokedex(fsys fs.FS) (*Pokedex, error) {
+	typeList := []Type{}
+	if err := readJSON(fsys, TYPES_FILE, &typeList); err != nil {
+		return nil, err
+	}
+	moveList := []*Move{}
+	if err := readJSON(fsys, MOVES_FILE, &moveList); err != nil {
+		return nil, err
+	}
+	pokemonList := []Pokemon{}
+	if err := readJSON(fsys, POKEMON_FILE, &pokemonList); err != nil {
+		return nil, err
+	}
+
+	// The level file is optional, without it the built in table is used
+	var levelMap map[float64]float64
+	levelList := []Level{}
+	if err := readJSON(fsys, LEVELS_FILE, &levelList); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	} else if err == nil {
+		if levelMap, err = loadLevels(levelList); err != nil {
+			return nil, err
+		}
+	}
+	return newPokedex(typeList, moveList, pokemonList, levelMap)
+}
+
+// newPokedex indexes decoded game data into a new Pokedex. The levels are
+// loaded first so max CPs use the game data's CP multipliers, or the built
+// in table if levelMap is nil.
+func newPokedex(typeList []Type, moveList []*Move, pokemonList []Pokemon, levelMap map[float64]float64) (*Pokedex, error) {
+	d := &Pokedex{
+		pokemonMap:    make(map[string]Pokemon),
+		dexMap:        make(map[int]string),
+		typeMap:       make(map[string]Type),
+		typeToID:      make(map[string]string),
+		moveMap:       make(map[string]*Move),
+		moveToID:      make(map[string]string),
+		multiplierMap: levelMap,
+	}
+
+	if err := d.loadTypes(typeList); err != nil {
+		return nil, err
+	}
+	if err := d.loadMoves(moveList); err != nil {
+		return nil, err
+	}
+	if err := d.loadPokemon(pokemonList); err != nil {
+		return nil, err
+	}
+	d.loadFamilies()
+	return d, nil
+}
+
+// DefaultPokedex returns the current Pokedex used by the package level
+// functions such as GetPokemon and GetType
+func DefaultPokedex() (*Pokedex, error) {
+	s, err := DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+	return s.Pokedex(), nil
+}
+
+// DefaultStore returns the Store behind the package level functions. On first
+// use it is loaded from JSON_LOCATION if that is set, or from the embedded
+// json files otherwise.
+func DefaultStore() (*Store, error) {
+	defaultLock.Lock()
+	defer defaultLock.Unlock()
+
+	if defaultStore == nil {
+		s, err := NewStore(LoadOptions{Dir: JSON_LOCATION})
+		if err != nil {
+			return nil, err
+		}
+		defaultStore = s
+	}
+	return defaultStore, nil
+}
+
+// SetDefaultStore replaces the Store behind the package level functions, so
+// they follow its reloads
+func SetDefaultStore(s *Store) {
+	defaultLock.Lock()
+	defaultStore = s
+	defaultLock.Unlock()
+}
+
+// SetDefaultPokedex replaces the Pokedex used by the package level functions
+func SetDefaultPokedex(d *Pokedex) {
+	SetDefaultStore(newStore(LoadOptions{Dir: JSON_LOCATION}, d))
+}
+
+func readJSON(fsys fs.FS, name string, v interface{}) error {
+	name = strings.TrimPrefix(name, "/")
+	file, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(file, v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// loadLevels builds a CP multiplier map from the whole levels in the level file
+func loadLevels(levelList []Level) (map[float64]float64, error) {
+	sort.Slice(levelList, func(i, j int) bool {
+		return levelList[i].Level < levelList[j].Level
+	})
+
+	cpMultipliers := []float64{}
+	for i, level := range levelList {
+		if level.Level != float64(i+1) || level.CPMultiplier <= 0 {
+			return nil, ERR_LEVELS
+		}
+		cpMultipliers = append(cpMultipliers, level.CPMultiplier)
+	}
+	if len(cpMultipliers) == 0 {
+		return nil, ERR_LEVELS
+	}
+	return newMultiplierMap(cpMultipliers), nil
+}
+
+func (d *Pokedex) loadTypes(typeList []Type) error {
+	if len(typeList) == 0 {
+		return ERR_NO_TYPES
+	}
+
+	for _, ty := range typeList {
+		ty.Thumbnail = "https://github.com/haynesherway/pogo/blob/master/pics/" + strings.ToLower(ty.Name) + ".png?raw=true"
+		//ty.Thumbnail = "https://github.com/PokeAPI/sprites/blob/master/sprites/items/"+strings.ToLower(ty.Name)+"-gem.png?raw=true"
+		ty.pokedex = d
+		d.typeMap[ty.ID] = ty
+		d.typeToID[strings.ToLower(ty.Name)] = ty.ID
+	}
+	d.typeMatrix = newTypeMatrix(d.typeMap)
+	return nil
+}
+
+func (d *Pokedex) loadMoves(moveList []*Move) error {
+	if len(moveList) == 0 {
+		return ERR_NO_MOVES
+	}
+
+	// Ids come first so a name never hides another move's id, and a name
+	// shared by several moves, such as a fast and a charge move, goes to
+	// the first one. Validate reports the others.
+	for _, move := range moveList {
+		d.moveMap[move.ID] = move
+		d.moveToID[moveKey(move.ID)] = move.ID
+	}
+	for _, move := range moveList {
+		if _, ok := d.moveToID[moveKey(move.Name)]; !ok {
+			d.moveToID[moveKey(move.Name)] = move.ID
+		}
+	}
+	return nil
+}
+
+func (d *Pokedex) loadPokemon(pokemonList []Pokemon) error {
+	if len(pokemonList) == 0 {
+		return ERR_NO_POKEMON
+	}
+
+	for _, poke := range pokemonList {
+		pokeID := pokemonKey(poke.ID)
+		poke.ID = pokeID
+		poke.Variant = variantFromID(pokeID)
+		poke.pokedex = d
+		poke.Moves.Fast = d.resolveMoves(poke.Moves.Fast)
+		poke.Moves.Charge = d.resolveMoves(poke.Moves.Charge)
+		if poke.MaxCP == 0 {
+			poke.MaxCP = d.getMaxCP(poke.Stats)
+		}
+		if poke.Variant != VARIANT_NORMAL {
+			poke.MegaEvolutions = nil
+		}
+		for _, m := range poke.MegaEvolutions {
+			if m.Pokemon == "" {
+				m.Pokemon = pokeID
+			}
+		}
+		d.pokemonList = append(d.pokemonList, poke)
+		d.pokemonMap[pokeID] = poke
+		if _, ok := d.dexMap[poke.Dex]; !ok {
+			d.dexMap[poke.Dex] = pokeID
+		}
+
+		for _, form := range poke.Forms {
+			thisForm := poke
+
+			// See if any changes to name need to be done
+			formID, formName := formName(form)
+			thisForm.ID = formID
+			thisForm.Name = formName
+			thisForm.Variant = variantFromID(formID)
+			if thisForm.Variant != VARIANT_NORMAL {
+				thisForm.MegaEvolutions = nil
+			}
+			if _, ok := d.pokemonMap[formID]; !ok {
+				d.pokemonMap[formID] = thisForm
+			}
+		}
+
+		for _, m := range poke.MegaEvolutions {
+			d.pokemonMap[m.megaID()] = m.megaPokemon(d, poke)
+		}
+	}
+
+	// Add aliases
+	for p, alias := range pokemonAliases {
+		for poke, data := range d.pokemonMap {
+			if strings.Contains(poke, p) {
+				newalias := fmt.Sprintf(alias, strings.Replace(poke, p, "", -1))
+				d.pokemonMap[newalias] = data
+			}
+		}
+	}
+
+	return nil
+}
+
+// formName returns the map id and display name for a form
+func formName(form *PokemonForm) (string, string) {
+	id, name := form.ID, form.Name
+	if len(id) > 7 && id[0:7] == "SPINDA_" {
+		lastNum, _ := strconv.Atoi(id[7:])
+		lastNum++
+		id = fmt.Sprintf("SPINDA_%d", lastNum)
+		name = fmt.Sprintf("Spinda %d", lastNum)
+	}
+	return pokemonKey(id), name
+}
+
+// pokemonKey turns a game data id such as RATTATA_ALOLA into rattata-alola
+func pokemonKey(id string) string {
+	return strings.Replace(strings.ToLower(id), "_", "-", -1)
+}
+
+// GetPokemon returns a Pokemon resource by name, id or dex number
+func (d *Pokedex) GetPokemon(pokemonName string) (*Pokemon, error) {
+	// Check if a dex number was sent
+	if dex, err := strconv.Atoi(pokemonName); err == nil {
+		if pk, ok := d.dexMap[dex]; ok {
+			pokemonName = pk
+		}
+	}
+
+	pokemonName = strings.ToLower(pokemonName)
+	if p, ok := d.pokemonMap[pokemonName]; ok {
+		p.Moves = p.Moves.clone()
+		p.GetSprite()
+		p.GetTypeEffects()
+		return &p, nil
+	}
+	return nil, ERR_NOT_FOUND
+}
+
+// AllPokemon returns every pokemon and form in the Pokedex, without aliases,
+// in the order they were loaded
+func (d *Pokedex) AllPokemon() []Pokemon {
+	all := []Pokemon{}
+	seen := map[string]bool{}
+	for _, poke := range d.pokemonList {
+		ids := []string{poke.ID}
+		for _, form := range poke.Forms {
+			formID, _ := formName(form)
+			ids = append(ids, formID)
+		}
+		for _, m := range poke.MegaEvolutions {
+			ids = append(ids, m.megaID())
+		}
+
+		for _, id := range ids {
+			if p, ok := d.pokemonMap[id]; ok && !seen[id] {
+				seen[id] = true
+				p.Moves = p.Moves.clone()
+				all = append(all, p)
+			}
+		}
+	}
+	return all
+}
+
+// GetType returns a Type resource by name
+func (d *Pokedex) GetType(t string) (*Type, error) {
+	t = strings.ToLower(t)
+	if ty, ok := d.typeMap[d.typeToID[t]]; ok {
+		ty.GetTypeEffects()
+		return &ty, nil
+	}
+	return &Type{}, ERR_TYPE_NOT_FOUND
+}
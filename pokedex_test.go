@@ -0,0 +1,196 @@
+package pogo
+
+import (
+	"math"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	options := map[string]LoadOptions{
+		"embedded": {},
+		"dir":      {Dir: "json"},
+		"fs":       {FS: os.DirFS("json")},
+	}
+	for name, opts := range options {
+		d, err := Load(opts)
+		if err != nil {
+			t.Error("Unable to load", name, "pokedex:", err)
+			continue
+		}
+		testPokedex(t, d)
+	}
+}
+
+func testPokedex(t *testing.T, d *Pokedex) {
+	for _, pokemon := range testMons {
+		p, err := d.GetPokemon(pokemon.input)
+		if err != nil {
+			t.Error("For", pokemon.input, "expected", pokemon.name, "got", err.Error())
+			continue
+		}
+		if p.Name != pokemon.name {
+			t.Error("For", pokemon.input, "expected", pokemon.name, "got", p.Name)
+		}
+	}
+
+	if _, err := d.GetType("dragon"); err != nil {
+		t.Error("For dragon expected type, got", err.Error())
+	}
+}
+
+func TestNewPokedexErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"missing files", fstest.MapFS{}},
+		{"bad json", fstest.MapFS{
+			"type.json":    {Data: []byte(`[{"id":"POKEMON_TYPE_NORMAL","name":"Normal"}]`)},
+			"move.json":    {Data: []byte(`[{"id":"TACKLE_FAST","name":"Tackle Fast"}]`)},
+			"pokemon.json": {Data: []byte(`{`)},
+		}},
+		{"no moves", fstest.MapFS{
+			"type.json":    {Data: []byte(`[{"id":"POKEMON_TYPE_NORMAL","name":"Normal"}]`)},
+			"move.json":    {Data: []byte(`[]`)},
+			"pokemon.json": {Data: []byte(`[{"id":"PIDGEY","name":"Pidgey"}]`)},
+		}},
+		{"no pokemon", fstest.MapFS{
+			"type.json":    {Data: []byte(`[{"id":"POKEMON_TYPE_NORMAL","name":"Normal"}]`)},
+			"move.json":    {Data: []byte(`[{"id":"TACKLE_FAST","name":"Tackle Fast"}]`)},
+			"pokemon.json": {Data: []byte(`[]`)},
+		}},
+	}
+
+	for _, test := range tests {
+		if d, err := NewPokedex(test.fsys); err == nil {
+			t.Error("For", test.name, "expected error, got", d)
+		}
+	}
+}
+
+func TestGetMove(t *testing.T) {
+	for _, name := range []string{"Hydro Pump", "hydro-pump", "HYDRO_PUMP"} {
+		m, err := GetMove(name)
+		if err != nil {
+			t.Error("For", name, "expected Hydro Pump, got", err.Error())
+			continue
+		}
+		if m.ID != "HYDRO_PUMP" || m.Power != 130 || m.EnergyDelta != -100 || m.Type.Name != "Water" {
+			t.Error("For", name, "got", m)
+		}
+	}
+
+	if _, err := GetMove("splash dance"); err != ERR_MOVE_NOT_FOUND {
+		t.Error("For splash dance expected", ERR_MOVE_NOT_FOUND, "got", err)
+	}
+
+	p, err := GetPokemon("bulbasaur")
+	if err != nil {
+		t.Fatal("Unable to get pokemon")
+	}
+	for _, m := range append(p.Moves.Fast, p.Moves.Charge...) {
+		if m.DurationMs == 0 {
+			t.Error("For", m.ID, "expected a resolved move, got", m)
+		}
+	}
+}
+
+func TestPokemonMovesAreCopies(t *testing.T) {
+	p, err := GetPokemon("bulbasaur")
+	if err != nil {
+		t.Fatal("Unable to get pokemon")
+	}
+	power := p.Moves.Fast[0].Power
+	p.Moves.Fast[0].Power = 1000
+
+	again, _ := GetPokemon("bulbasaur")
+	if again.Moves.Fast[0].Power != power {
+		t.Error("Expected the Pokedex's move to be unchanged, got", again.Moves.Fast[0].Power)
+	}
+	if m, _ := GetMove(p.Moves.Fast[0].ID); m.Power != power {
+		t.Error("Expected GetMove to be unchanged, got", m.Power)
+	}
+}
+
+func TestLoadLevels(t *testing.T) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		t.Fatal("Unable to load pokedex:", err)
+	}
+	if d.multiplierMap == nil {
+		t.Fatal("Expected multipliers from the level file")
+	}
+	for _, level := range d.getLevels() {
+		if math.Abs(d.getMultiplier(level)-multiplierMap[level]) > 1e-9 {
+			t.Error("For level", level, "level file has", d.getMultiplier(level), "built in table has", multiplierMap[level])
+		}
+	}
+
+	// Half levels come from the whole levels either side
+	if cpm := d.getMultiplier(5.5); math.Abs(cpm-0.30605738) > 1e-8 {
+		t.Error("Expected level 5.5 multiplier 0.30605738, got", cpm)
+	}
+
+	for _, levels := range []string{`[]`, `[{"level":2,"cpMultiplier":0.16639787}]`, `[{"level":1,"cpMultiplier":0}]`} {
+		fsys := testStoreFS("Pidgey")
+		fsys["level.json"] = &fstest.MapFile{Data: []byte(levels)}
+		if _, err := NewPokedex(fsys); err != ERR_LEVELS {
+			t.Error("For", levels, "expected levels error, got", err)
+		}
+	}
+}
+
+// withBrokenDefault runs f with a default Pokedex that can't be loaded
+func withBrokenDefault(t *testing.T, f func()) {
+	store, err := DefaultStore()
+	if err != nil {
+		t.Fatal("Unable to load default store:", err)
+	}
+	location := JSON_LOCATION
+	JSON_LOCATION = "/nonexistent"
+	SetDefaultStore(nil)
+	defer func() {
+		JSON_LOCATION = location
+		SetDefaultStore(store)
+	}()
+
+	if _, err := DefaultPokedex(); err == nil {
+		t.Fatal("Expected the default Pokedex to fail to load")
+	}
+	f()
+}
+
+func TestDefaultPokedexErrors(t *testing.T) {
+	p := &Pokemon{ID: "pidgey", Types: TypeList{{ID: "POKEMON_TYPE_NORMAL"}}, Stats: PokemonStats{BaseAttack: 85, BaseDefense: 73, BaseStamina: 120}}
+	fighting := &Move{Power: 10, Type: PokemonType{ID: "POKEMON_TYPE_FIGHTING"}}
+
+	withBrokenDefault(t, func() {
+		if _, err := GetFamily("pidgey"); err == nil {
+			t.Error("Expected an error for GetFamily")
+		}
+		if _, err := GetAttackTypeScalars("POKEMON_TYPE_FIRE"); err == nil {
+			t.Error("Expected an error for GetAttackTypeScalars")
+		}
+		if _, err := GetDefenseTypeScalars("POKEMON_TYPE_FIRE"); err == nil {
+			t.Error("Expected an error for GetDefenseTypeScalars")
+		}
+
+		if _, err := p.GetShadow(); err == nil || err == ERR_NOT_FOUND {
+			t.Error("Expected the load error from GetShadow, got", err)
+		}
+		if _, err := p.GetPvPRanking(GREAT_LEAGUE, 0); err == nil {
+			t.Error("Expected the load error from GetPvPRanking")
+		}
+		if cp := p.GetCP(20, 15, 15, 15); cp != 388 {
+			t.Error("Expected CP from the built in multipliers, got", cp)
+		}
+		if damage := GetDamage(fighting, p, 100, p, 100); damage != 6 {
+			t.Error("Expected neutral damage, got", damage)
+		}
+		if steps := p.Evolutions(); len(steps) != 0 {
+			t.Error("Expected no evolutions, got", steps)
+		}
+	})
+}
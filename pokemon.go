@@ -5,24 +5,26 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"math"
 	"net/http"
 	"os"
-	"sort"
-	"strconv"
 	"strings"
 )
 
 //const POKE_API = "http://pokeapi.co/api/v2/"
 const HAYNESBOT_IMG = "https://github.com/haynesherway/pogo/blob/master/pics/"
 
-// Locations of the json files
+// Locations of the json files. JSON_LOCATION is empty by default so the
+// embedded copy of the json directory is used; set it to load newer files.
 var (
-	JSON_LOCATION = os.Getenv("GOPATH") + "/src/github.com/haynesherway/pogo/json/"
+	JSON_LOCATION = ""
 	POKEMON_FILE  = "/pokemon.json"
 	MOVES_FILE    = "/move.json"
 	TYPES_FILE    = "/type.json"
+	LEVELS_FILE   = "/level.json"
 	ICONS_FILE    = "/home/pi/Public/Images/pokemon_icons/"
 	ASSETS_FILE   = "/home/pi/Public/Images/PogoAssets/pokemon_icons/"
 )
@@ -32,9 +34,6 @@ var (
 	ERR_NOT_FOUND = errors.New("Pokemon not found.")
 )
 
-var pokemonMap map[string]Pokemon
-var dexMap map[int]string
-
 // Pokemon is a resource representing a single pokemon
 type Pokemon struct {
 	Name  string       `json:"name"`
@@ -44,10 +43,19 @@ type Pokemon struct {
 	Forms FormList     `json:"forms"`
 	Stats PokemonStats `json:"stats"`
 	Moves
-	MaxCP int `json:"maxCP"`
+	MaxCP       int       `json:"maxCP"`
+	Variant     Variant   `json:"variant,omitempty"`
+	CandyFamily Family    `json:"family"`
+	Evolution   Evolution `json:"evolution"`
+	Encounter   Encounter `json:"encounter"`
+
+	MegaEvolutions []*MegaEvolution `json:"megaEvolutions,omitempty"`
+	Mega           *MegaEvolution   `json:"mega,omitempty"` // set on mega forms only
 	Icons
 	TypeRelations
 	API
+
+	pokedex *Pokedex
 }
 
 var pokemonAliases = map[string]string{
@@ -59,6 +67,8 @@ var pokemonAliases = map[string]string{
 	"-normal":          "%s",
 	"deoxys-":          "%s-deoxys",
 	"galarian-":        "%s-galarian",
+	"-mega":            "mega-%s",
+	"-primal":          "primal-%s",
 }
 
 type Icons struct {
@@ -105,8 +115,8 @@ type PokemonStats struct {
 
 func (t TypeRelation) Print() string {
 	str := []string{}
-	for _, ty := range t {
-		str = append(str, ty)
+	for _, e := range t {
+		str = append(str, e.Print())
 	}
 	return strings.Join(str, ", ")
 }
@@ -119,24 +129,22 @@ func (t TypeRelation) Len() int {
 	return ct
 }
 
-// GetPokemon returns a Pokemon resource
+// GetPokemon returns a Pokemon resource from the default Pokedex
 func GetPokemon(pokemonName string) (*Pokemon, error) {
-	// Check if a dex number was sent
-	// pokemonName =  strings.Replace(pokemonName, "-", " ", 1)
-	if dex, err := strconv.Atoi(pokemonName); err == nil {
-		if pk, ok := dexMap[dex]; ok {
-			pokemonName = pk
-		}
+	d, err := DefaultPokedex()
+	if err != nil {
+		return nil, err
 	}
+	return d.GetPokemon(pokemonName)
+}
 
-	pokemonName = strings.ToLower(pokemonName)
-	if p, ok := pokemonMap[pokemonName]; ok {
-		p.GetSprite()
-		p.GetTypeEffects()
-		return &p, nil
-	} else {
-		return nil, ERR_NOT_FOUND
+// getPokedex returns the Pokedex the pokemon was loaded from. A pokemon built
+// by hand uses the default Pokedex, or gets the error loading it.
+func (p *Pokemon) getPokedex() (*Pokedex, error) {
+	if p.pokedex != nil {
+		return p.pokedex, nil
 	}
+	return DefaultPokedex()
 }
 
 func (p *Pokemon) GetSprite() {
@@ -178,81 +186,76 @@ func (p *Pokemon) GetMaxCP() (cp int) {
 }
 
 func (p *Pokemon) GetCP(level float64, ivAttack int, ivDefense int, ivStamina int) (cp int) {
-	attack := getStatValue(p.Stats.BaseAttack, ivAttack, level)
-	defense := getStatValue(p.Stats.BaseDefense, ivDefense, level)
-	stamina := getStatValue(p.Stats.BaseStamina, ivStamina, level)
-
-	cp = calculateCP(attack, defense, stamina, level)
-	return
+	return p.GetStats(level, ivAttack, ivDefense, ivStamina, false).CP
 }
 
 func (p *Pokemon) GetHP(level float64, ivStamina int) (hp int) {
-	stamina := getStatValue(p.Stats.BaseStamina, ivStamina, level)
-	hp = calculateHP(stamina, level)
-	return
+	return p.GetStats(level, 0, 0, ivStamina, false).HP
 }
 
+// GetRaidCPChart returns the raid CP chart, see GetCPChart
 func (p *Pokemon) GetRaidCPChart() ([]IVStat, string) {
-	possibleIVs := []int{15, 14, 13, 12, 11, 10}
+	return p.GetCPChart(CONTEXT_RAID.Context())
+}
 
-	//ivList := map[int]map[int]map[int]
+// GetLevelCPChart returns the CP and HP of the pokemon with the given IVs at
+// every whole level, up to the best buddy level
+func (p *Pokemon) GetLevelCPChart(ivAttack int, ivDefense int, ivStamina int) ([]IVStat, string) {
 	ivs := []IVStat{}
 
-	str := "[ % ]Ak|Df|St[ 20 | 25 ]\n"
-	str += "------------------------\n"
-	for _, a := range possibleIVs {
-		for _, d := range possibleIVs {
-			for _, s := range possibleIVs {
-				percent := round(float64((a+d+s)*100) / float64(45))
-
-				cp15 := p.GetCP(15.0, a, d, s)
-				cp20 := p.GetCP(20.0, a, d, s)
-				cp25 := p.GetCP(25.0, a, d, s)
-				iv := IVStat{
-					Attack:  a,
-					Defense: d,
-					Stamina: s,
-					CP15:    cp15,
-					CP20:    cp20,
-					CP25:    cp25,
-					Percent: percent,
-				}
-				ivs = append(ivs, iv)
-			}
-		}
-	}
-
-	ivs = SortChart(ivs)
+	str := "|Lvl |  CP   |  HP  |\n"
+	str += "|----|-------|------|\n"
 	chart := []string{}
-	for _, iv := range ivs {
-		chart = append(chart, iv.PrintChartRow())
+	d, _ := p.getPokedex()
+	for _, l := range d.getLevels() {
+		if l != math.Floor(l) || l > MAX_BUDDY_LEVEL {
+			continue
+		}
+		stats := p.GetStats(l, ivAttack, ivDefense, ivStamina, false)
+		iv := IVStat{
+			Level:   l,
+			Attack:  ivAttack,
+			Defense: ivDefense,
+			Stamina: ivStamina,
+			CP:      stats.CP,
+			HP:      stats.HP,
+			Percent: round(float64((ivAttack+ivDefense+ivStamina)*100) / float64(45)),
+		}
+		ivs = append(ivs, iv)
+		chart = append(chart, iv.PrintLevelRow())
 	}
 
 	return ivs, str + strings.Join(chart, "\n")
 }
 
+// GetRaidCPRange returns the raid CP range, see GetCPRange
 func (p *Pokemon) GetRaidCPRange() string {
-	min20 := p.GetCP(20.0, 10, 10, 10)
-	max20 := p.GetCP(20.0, 15, 15, 15)
-	min25 := p.GetCP(25.0, 10, 10, 10)
-	max25 := p.GetCP(25.0, 15, 15, 15)
-	return fmt.Sprintf("Level 20: %v - **%v**\nLevel 25: %v - **%v**", min20, max20, min25, max25)
+	return p.GetCPRange(CONTEXT_RAID.Context())
 }
 
 func (p *Pokemon) GetIV(cp int, hp int, level float64, stardust int, best string) ([]IVStat, string) {
+	return p.GetIVAppraisal(cp, hp, level, stardust, Appraisal{Best: best})
+}
+
+// GetIVAppraisal returns the possible IVs for a pokemon's CP and HP that fit
+// the appraisal, using the level or stardust cost when they are given
+func (p *Pokemon) GetIVAppraisal(cp int, hp int, level float64, stardust int, appraisal Appraisal) ([]IVStat, string) {
 	IVStat := &IVStat{
-		Level:    level,
-		CP:       cp,
-		HP:       hp,
-		Stardust: stardust,
-		Best:     best,
+		Level:     level,
+		CP:        cp,
+		HP:        hp,
+		Stardust:  stardust,
+		Best:      appraisal.Best,
+		Appraisal: appraisal,
 	}
 	return p.getIV(IVStat)
 }
 
 func (p *Pokemon) getIV(stats *IVStat) ([]IVStat, string) {
-	possibleIVs := []int{15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0}
-
+	dex, err := p.getPokedex()
+	if err != nil {
+		return nil, ""
+	}
 	possibleLevels := []float64{}
 	if stats.Level != 0.0 {
 		possibleLevels = append(possibleLevels, stats.Level)
@@ -260,64 +263,42 @@ func (p *Pokemon) getIV(stats *IVStat) ([]IVStat, string) {
 		if _, ok := stardustMap[stats.Stardust]; ok {
 			possibleLevels = stardustMap[stats.Stardust]
 		} else {
-			for k := range multiplierMap {
-				possibleLevels = append(possibleLevels, k)
-			}
+			possibleLevels = dex.getLevels()
 		}
 	} else {
-		for k := range multiplierMap {
-			possibleLevels = append(possibleLevels, k)
-		}
+		possibleLevels = dex.getLevels()
 	}
 	cp := stats.CP
 	hp := stats.HP
+	appraisal := stats.Appraisal
+	if appraisal.Best == "" {
+		appraisal.Best = stats.Best
+	}
 
 	ivList := []IVStat{}
 
 	message := "|Lvl | At | Df | St |%%%|   \n"
 	message += "|----|----|----|----|---|   \n"
 
+	levels := map[float64]bool{}
 	for _, l := range possibleLevels {
-		for _, a := range possibleIVs {
-			for _, d := range possibleIVs {
-				for _, s := range possibleIVs {
-					calccp := p.GetCP(l, a, d, s)
-					calchp := p.GetHP(l, s)
-
-					if stats.Best != "" {
-						beststr := ""
-						vals := []int{a, d, s}
-						sort.Ints(vals)
-						highest := vals[2]
-						if a == highest {
-							beststr += "a"
-						}
-						if d == highest {
-							beststr += "d"
-						}
-						if s == highest {
-							beststr += "s"
-						}
-						if beststr != stats.Best {
-							continue
-						}
-					}
-					if cp == calccp && hp == calchp {
-						perc := round(float64((a+d+s)*100) / float64(45))
-						stat := IVStat{
-							Level:   l,
-							Attack:  a,
-							Defense: d,
-							Stamina: s,
-							//CP20: cp20,
-							//CP25: cp25,
-							Percent: perc,
-						}
-						ivList = append(ivList, stat)
-					}
-				}
-			}
+		levels[l] = true
+	}
+
+	for _, c := range dex.getCPCandidates(p, cp, hp) {
+		l, a, d, s := c.level(), int(c.attack), int(c.defense), int(c.stamina)
+		if !levels[l] || !appraisal.Matches(a, d, s) {
+			continue
+		}
+		perc := round(float64((a+d+s)*100) / float64(45))
+		stat := IVStat{
+			Level:   l,
+			Attack:  a,
+			Defense: d,
+			Stamina: s,
+			Percent: perc,
 		}
+		ivList = append(ivList, stat)
 	}
 
 	if ivList == nil || len(ivList) == 0 {
@@ -339,55 +320,9 @@ func (p *Pokemon) getIV(stats *IVStat) ([]IVStat, string) {
 	return ivList, message + strings.Join(chart, "\n")
 }
 
+// GetRaidIV returns the IVs a raid or research pokemon with a CP could have
 func (p *Pokemon) GetRaidIV(raidcp int) ([]IVStat, string) {
-	possibleIVs := []int{15, 14, 13, 12, 11, 10}
-
-	ivList := []IVStat{}
-
-	//message := fmt.Sprintf("Possible IVs for **%s** with CP of **%d**:\n", p.Name, raidcp)
-
-	message := "| At | Df | St | %%% | \n"
-	message += "|----|----|----|-----| \n"
-	for _, a := range possibleIVs {
-		for _, d := range possibleIVs {
-			for _, s := range possibleIVs {
-				cp15 := p.GetCP(15.0, a, d, s)
-				cp20 := p.GetCP(20.0, a, d, s)
-				cp25 := p.GetCP(25.0, a, d, s)
-				if raidcp == cp20 || raidcp == cp25 || raidcp == cp15 {
-					lvl := 20.0
-					if raidcp == cp25 {
-						lvl = 25.0
-					} else if raidcp == cp15 {
-						lvl = 15.0
-					}
-					perc := round(float64((a+d+s)*100) / float64(45))
-					stat := IVStat{
-						Attack:  a,
-						Defense: d,
-						Stamina: s,
-						Level:   lvl,
-						//CP20: cp20,
-						//CP25: cp25,
-						Percent: perc,
-					}
-					ivList = append(ivList, stat)
-				}
-			}
-		}
-	}
-
-	if ivList == nil || len(ivList) == 0 {
-		return ivList, ""
-	}
-
-	ivList = SortChart(ivList)
-	chart := []string{}
-	for _, s := range ivList {
-		chart = append(chart, s.PrintRaidIVRow())
-	}
-
-	return ivList, message + strings.Join(chart, "\n")
+	return p.GetEncounterIV(raidcp, CONTEXT_RESEARCH.Context(), CONTEXT_RAID.Context())
 }
 
 func (p *Pokemon) GetTypeRelations() (relations map[string]map[string]float64) {
@@ -395,8 +330,12 @@ func (p *Pokemon) GetTypeRelations() (relations map[string]map[string]float64) {
 	relations["attack"] = make(map[string]float64)
 	relations["defense"] = make(map[string]float64)
 
+	d, err := p.getPokedex()
+	if err != nil {
+		return
+	}
 	for _, pt := range p.Types {
-		attackScalars := GetAttackTypeScalars(pt.ID)
+		attackScalars := d.GetAttackTypeScalars(pt.ID)
 		for tName, tScalar := range attackScalars {
 			if _, ok := relations["attack"][tName]; !ok {
 				relations["attack"][tName] = 1
@@ -404,7 +343,7 @@ func (p *Pokemon) GetTypeRelations() (relations map[string]map[string]float64) {
 			relations["attack"][tName] = relations["attack"][tName] * tScalar
 		}
 
-		defenseScalars := GetDefenseTypeScalars(pt.ID)
+		defenseScalars := d.GetDefenseTypeScalars(pt.ID)
 		for tName, tScalar := range defenseScalars {
 			if _, ok := relations["defense"][tName]; !ok {
 				relations["defense"][tName] = 1
@@ -416,63 +355,28 @@ func (p *Pokemon) GetTypeRelations() (relations map[string]map[string]float64) {
 	return
 }
 
+// GetTypeEffects fills in the types the pokemon's types are strong and weak
+// against, and the types it is weak to and resists
 func (p *Pokemon) GetTypeEffects() {
 	if p.SuperEffective.Len() != 0 {
 		return
 	}
 
-	typeRelations := p.GetTypeRelations()
-
-	//p.SuperEffective = []TypeRelation{}
-	//p.NotEffective = []TypeRelationstring{}
-	//p.Weakness = []TypeRelationstring{}
-	//p.Resistance = TypeRelation}
-
-	//Attack
-	for ty, sc := range typeRelations["attack"] {
-		if sc > 1.9 {
-			p.SuperEffective = append(p.SuperEffective, ty+"(x2)")
-		} else if sc >= 1.4 {
-			p.SuperEffective = append(p.SuperEffective, ty)
-		} else if sc <= .6 {
-			p.NotEffective = append(p.NotEffective, ty+"(x2)")
-		} else if sc <= .8 {
-			p.NotEffective = append(p.NotEffective, ty)
+	d, _ := p.getPokedex()
+	p.TypeRelations = d.typeRelations(func(id string) float64 {
+		// Attacking with every one of the pokemon's types
+		scalar := 1.0
+		for _, pt := range p.Types {
+			scalar *= d.getScalar(pt.ID, id)
 		}
-	}
-
-	//Defense
-	for ty, sc := range typeRelations["defense"] {
-		if sc > 1.9 {
-			p.Weakness = append(p.Weakness, ty+"(x2)")
-		} else if sc >= 1.4 {
-			p.Weakness = append(p.Weakness, ty)
-		} else if sc <= .6 {
-			p.Resistance = append(p.Resistance, ty+"(x2)")
-		} else if sc <= .8 {
-			p.Resistance = append(p.Resistance, ty)
+		return scalar
+	}, func(id string) float64 {
+		scalar := 1.0
+		for _, pt := range p.Types {
+			scalar *= d.getScalar(id, pt.ID)
 		}
-	}
-
-	/*
-	   msg := fmt.Sprintf("Type Effects for **%s** (%s):\n", p.Name, p.Types.Print())
-	   /*if len(doubleEffective) > 0 {
-	       msg += fmt.Sprintf("Double Effective Against: %s\n", strings.Join(doubleEffective, ", "))
-	   }
-	   if len(superEffective) > 0 {
-	       msg += fmt.Sprintf("Super Effective Against: %s\n", strings.Join(superEffective, ", "))
-	   }
-	   if len(notEffective) > 0 {
-	       msg += fmt.Sprintf("Not Very Effective Against: %s\n", strings.Join(notEffective, ", "))
-	   }
-	   if len(weakness) > 0 {
-	       msg += fmt.Sprintf("Weak To: %s\n", strings.Join(weakness, ", "))
-	   }
-	   if len(resistance) > 0 {
-	       msg += fmt.Sprintf("Resistant To: %s\n", strings.Join(resistance, ", "))
-	   }*/
-
-	return
+		return scalar
+	})
 }
 
 /* for _, pt := range Pokemon.Types {
@@ -564,38 +468,11 @@ func ImageLocation(name string) string {
 	return ICONS_FILE + name
 }
 
-func init() {
-
-	pokemonMap = make(map[string]Pokemon)
-	dexMap = make(map[int]string)
-
-	//Pokemon
-	file, err := ioutil.ReadFile(JSON_LOCATION + POKEMON_FILE)
-	if err != nil {
-		fmt.Println(err.Error())
-		return
-	}
-
-	writeFile, err := os.Create("pokemonNames.csv")
-	defer writeFile.Close()
-
-	pokemonList := []Pokemon{}
-	err = json.Unmarshal(file, &pokemonList)
-	if err != nil {
-		fmt.Println(err.Error())
-		return
-	}
-
-	for _, poke := range pokemonList {
-
-		if strings.Contains(strings.ToUpper(poke.ID), "PURIFIED") || strings.Contains(strings.ToUpper(poke.ID), "SHADOW") {
-			continue
-		}
-
-		pokeID := strings.Replace(strings.ToLower(poke.ID), "_", "-", -1)
-		poke.ID = pokeID
-		pokemonMap[pokeID] = poke
-		dexMap[poke.Dex] = pokeID
+// LinkImages links any missing pokemon icons into ICONS_FILE from the game
+// assets in ASSETS_FILE
+func (d *Pokedex) LinkImages() {
+	for _, poke := range d.pokemonList {
+		pokeID := poke.ID
 
 		// See if pokemon image exists
 		if !ImageExists(fmt.Sprintf("%s.png", pokeID)) {
@@ -634,81 +511,56 @@ func init() {
 			}
 		}
 
-		if poke.Forms.Len() > 0 {
-			for _, form := range poke.Forms {
-				thisForm := poke
-
-				// See if any changes to name need to be done
-				if len(form.ID) > 6 && form.ID[0:6] == "SPINDA" {
-					lastNum, _ := strconv.Atoi(form.ID[8:9])
-					lastNum++
-					form.ID = fmt.Sprintf("SPINDA_%d", lastNum)
-					form.Name = fmt.Sprintf("Spinda %d", lastNum)
+		for _, form := range poke.Forms {
+			formID, _ := formName(form)
+			// See if pokemon image exists
+			if !ImageExists(fmt.Sprintf("%s.png", formID)) {
+				// Try to get from pogo assets
+				f := form.AssetBundleValue
+				suffix := form.AssetBundleSuffix
+				oldImage := ""
+				if suffix == "" {
+					oldImage = fmt.Sprintf("pokemon_icon_%03d_%02d.png", poke.Dex, f)
+				} else {
+					oldImage = fmt.Sprintf("pokemon_icon%s.png", suffix)
 				}
-				formID := strings.Replace(strings.ToLower(form.ID), "_", "-", -1)
-				thisForm.ID = formID
-				thisForm.Name = form.Name
-				//thisForm.Name = strings.Title(strings.ToLower(strings.Replace(form.ID, "_", " ", -1)))
-				if _, ok := pokemonMap[formID]; !ok {
-					pokemonMap[formID] = thisForm
+				newImage := fmt.Sprintf("%s.png", formID)
+				if AssetsImageExists(oldImage) {
+					LinkFromAssets(oldImage, newImage)
 				}
-				// See if pokemon image exists
-				if !ImageExists(fmt.Sprintf("%s.png", formID)) {
-					// Try to get from pogo assets
-					f := form.AssetBundleValue
-					suffix := form.AssetBundleSuffix
-					oldImage := ""
-					if suffix == "" {
-						oldImage = fmt.Sprintf("pokemon_icon_%03d_%02d.png", poke.Dex, f)
-					} else {
-						oldImage = fmt.Sprintf("pokemon_icon%s.png", suffix)
-					}
-					newImage := fmt.Sprintf("%s.png", formID)
-					if AssetsImageExists(oldImage) {
-						LinkFromAssets(oldImage, newImage)
-					}
+			}
+			if !ImageExists(fmt.Sprintf("%s-shiny.png", formID)) {
+				f := form.AssetBundleValue
+				suffix := form.AssetBundleSuffix
+				oldImage := ""
+				if suffix == "" {
+					oldImage = fmt.Sprintf("pokemon_icon_%03d_%02d_shiny.png", poke.Dex, f)
+				} else {
+					oldImage = fmt.Sprintf("pokemon_icon%s_shiny.png", suffix)
 				}
-				if !ImageExists(fmt.Sprintf("%s-shiny.png", formID)) {
-					f := form.AssetBundleValue
-					suffix := form.AssetBundleSuffix
-					oldImage := ""
-					if suffix == "" {
-						oldImage = fmt.Sprintf("pokemon_icon_%03d_%02d_shiny.png", poke.Dex, f)
-					} else {
-						oldImage = fmt.Sprintf("pokemon_icon%s_shiny.png", suffix)
-					}
-					newImage := fmt.Sprintf("%s-shiny.png", formID)
-					if AssetsImageExists(oldImage) {
-						LinkFromAssets(oldImage, newImage)
-					}
+				newImage := fmt.Sprintf("%s-shiny.png", formID)
+				if AssetsImageExists(oldImage) {
+					LinkFromAssets(oldImage, newImage)
 				}
 			}
 		}
 	}
+}
 
-	for id, pokemon := range pokemonMap {
+// WriteNames writes a csv row of dex number, name, id and image status for
+// every pokemon in the Pokedex
+func (d *Pokedex) WriteNames(w io.Writer) error {
+	for id, pokemon := range d.pokemonMap {
 		img := ""
 		if err := pokemon.getNormal(); err != nil {
 			img = "No image found"
 		}
-		_, err := writeFile.WriteString(fmt.Sprintf("%v,%s,%s,%s\n", pokemon.Dex, pokemon.Name, id, img))
+		_, err := fmt.Fprintf(w, "%v,%s,%s,%s\n", pokemon.Dex, pokemon.Name, id, img)
 		if err != nil {
-			fmt.Println(err)
-		}
-	}
-
-	// Add aliases
-	for p, alias := range pokemonAliases {
-		for poke, data := range pokemonMap {
-			if strings.Contains(poke, p) {
-				newalias := fmt.Sprintf(alias, strings.Replace(poke, p, "", -1))
-				pokemonMap[newalias] = data
-
-			}
+			return err
 		}
 	}
-
-	return
+	return nil
 }
 
 func (p *API) getSprite() error {
@@ -12,10 +12,10 @@ type testMon struct {
 }
 
 var testMons = []testMon{
-	{"rayquaza", "Rayquaza", 3645},
-	{"Regirock", "Regirock", 3087},
-	{"mewtwo", "Mewtwo", 3982},
-	{"weedle", "Weedle", 397},
+	{"rayquaza", "Rayquaza", 3835},
+	{"Regirock", "Regirock", 3122},
+	{"mewtwo", "Mewtwo", 4178},
+	{"weedle", "Weedle", 456},
 }
 
 func TestGetPokemon(t *testing.T) {
@@ -72,6 +72,29 @@ func TestPokemon_GetMaxCP(t *testing.T) {
 	}
 }
 
+// TestMaxCPAllPokemon checks the CP formula and multipliers against the maxCP
+// shipped for every species and form
+func TestMaxCPAllPokemon(t *testing.T) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		t.Fatal("Unable to load pokedex:", err)
+	}
+
+	checked := 0
+	for _, p := range d.AllPokemon() {
+		if p.MaxCP == 0 {
+			continue
+		}
+		checked++
+		if cp := p.GetCP(40, 15, 15, 15); cp != p.MaxCP {
+			t.Error("For", p.ID, "maxCP is", p.MaxCP, "but level 40 15/15/15 is", cp)
+		}
+	}
+	if checked == 0 {
+		t.Error("Expected pokemon with maxCP")
+	}
+}
+
 func ExamplePokemon_GetMaxCP() {
 	pokemon, err := GetPokemon("weedle")
 	if err != nil {
@@ -80,7 +103,7 @@ func ExamplePokemon_GetMaxCP() {
 		fmt.Println("Max CP for Weedle is", pokemon.GetMaxCP())
 	}
 	// Output:
-	// Max CP for Weedle is 397
+	// Max CP for Weedle is 456
 }
 
 func ExamplePokemon_GetCP() {
@@ -92,5 +115,5 @@ func ExamplePokemon_GetCP() {
 		fmt.Println("CP for Groudon at level 20 with 15/14/15 IVs is", cp)
 	}
 	// Output:
-	// CP for Groudon at level 20 with 15/14/15 IVs is 2323
+	// CP for Groudon at level 20 with 15/14/15 IVs is 2346
 }
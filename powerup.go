@@ -0,0 +1,125 @@
+package pogo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors
+var (
+	ERR_INVALID_TARGET = errors.New("Invalid power up target.")
+	ERR_CP_UNREACHABLE = errors.New("Target CP can't be reached.")
+)
+
+// PowerUpTarget is where to stop powering up. Set one of Level, CP or
+// LeagueCap: power up to a level, until the CP is at least CP, or as high as
+// possible without going over LeagueCap.
+type PowerUpTarget struct {
+	Level     float64 `json:"level,omitempty"`
+	CP        int     `json:"cp,omitempty"`
+	LeagueCap int     `json:"leagueCap,omitempty"`
+}
+
+// PowerUpStep is a single power up, with the level and CP after it
+type PowerUpStep struct {
+	Level float64 `json:"level"`
+	CP    int     `json:"cp"`
+	PowerUpCost
+}
+
+// PowerUpPlan is every power up needed to get from one level to another
+type PowerUpPlan struct {
+	From  float64       `json:"from"`
+	To    float64       `json:"to"`
+	CP    int           `json:"cp"`
+	Steps []PowerUpStep `json:"steps"`
+	Total PowerUpCost   `json:"total"`
+	Lucky bool          `json:"lucky,omitempty"`
+}
+
+// PlanPowerUp returns the power ups needed to take the pokemon from a level
+// to the target, with the cost and CP of each step. Shadow and purified
+// costs apply, and lucky pokemon need half the stardust.
+func (p *Pokemon) PlanPowerUp(level float64, ivAttack int, ivDefense int, ivStamina int, target PowerUpTarget, lucky bool) (*PowerUpPlan, error) {
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	if d.getMultiplier(level) == 0 || level > MAX_LEVEL {
+		return nil, ERR_INVALID_LEVEL
+	}
+
+	// Work out the level to stop at
+	to := level
+	switch {
+	case target.Level != 0:
+		if d.getMultiplier(target.Level) == 0 || target.Level > MAX_LEVEL {
+			return nil, ERR_INVALID_LEVEL
+		}
+		if target.Level < level {
+			return nil, ERR_INVALID_TARGET
+		}
+		to = target.Level
+	case target.CP != 0:
+		for p.GetCP(to, ivAttack, ivDefense, ivStamina) < target.CP {
+			if to >= MAX_LEVEL {
+				return nil, ERR_CP_UNREACHABLE
+			}
+			to += 0.5
+		}
+	case target.LeagueCap != 0:
+		if p.GetCP(level, ivAttack, ivDefense, ivStamina) > target.LeagueCap {
+			return nil, ERR_CP_UNREACHABLE
+		}
+		for to < MAX_LEVEL && p.GetCP(to+0.5, ivAttack, ivDefense, ivStamina) <= target.LeagueCap {
+			to += 0.5
+		}
+	default:
+		return nil, ERR_INVALID_TARGET
+	}
+
+	plan := &PowerUpPlan{
+		From:  level,
+		To:    to,
+		CP:    p.GetCP(level, ivAttack, ivDefense, ivStamina),
+		Steps: []PowerUpStep{},
+		Lucky: lucky,
+	}
+	for l := level; l < to; l += 0.5 {
+		cost := p.GetPowerUpCost(l)
+		if lucky {
+			cost.Stardust = (cost.Stardust + 1) / 2
+		}
+		step := PowerUpStep{
+			Level:       l + 0.5,
+			CP:          p.GetCP(l+0.5, ivAttack, ivDefense, ivStamina),
+			PowerUpCost: cost,
+		}
+		plan.Steps = append(plan.Steps, step)
+		plan.CP = step.CP
+		plan.Total.Stardust += cost.Stardust
+		plan.Total.Candy += cost.Candy
+		plan.Total.XLCandy += cost.XLCandy
+	}
+	return plan, nil
+}
+
+func (c PowerUpCost) Print() string {
+	costs := []string{fmt.Sprintf("%d stardust", c.Stardust)}
+	if c.Candy > 0 {
+		costs = append(costs, fmt.Sprintf("%d candy", c.Candy))
+	}
+	if c.XLCandy > 0 {
+		costs = append(costs, fmt.Sprintf("%d XL candy", c.XLCandy))
+	}
+	return strings.Join(costs, ", ")
+}
+
+func (plan *PowerUpPlan) Print() string {
+	str := fmt.Sprintf("Level %v to %v: %s, CP %d\n", plan.From, plan.To, plan.Total.Print(), plan.CP)
+	for _, step := range plan.Steps {
+		str += fmt.Sprintf("|%4.1f| %5d | %s\n", step.Level, step.CP, step.PowerUpCost.Print())
+	}
+	return strings.TrimSuffix(str, "\n")
+}
@@ -0,0 +1,62 @@
+package pogo
+
+import (
+	"testing"
+)
+
+func TestPlanPowerUp(t *testing.T) {
+	mewtwo, err := GetPokemon("mewtwo")
+	if err != nil {
+		t.Fatal("Unable to get mewtwo:", err)
+	}
+	bulbasaur, _ := GetPokemon("bulbasaur")
+	shadow, _ := GetPokemon("bulbasaur-shadow")
+
+	tests := []struct {
+		poke   *Pokemon
+		level  float64
+		target PowerUpTarget
+		lucky  bool
+		to     float64
+		total  PowerUpCost
+	}{
+		{bulbasaur, 1, PowerUpTarget{Level: 40}, false, 40, PowerUpCost{Stardust: 270000, Candy: 304}},
+		{bulbasaur, 1, PowerUpTarget{Level: 40}, true, 40, PowerUpCost{Stardust: 135000, Candy: 304}},
+		{shadow, 1, PowerUpTarget{Level: 40}, false, 40, PowerUpCost{Stardust: 324000, Candy: 406}},
+		{mewtwo, 40, PowerUpTarget{Level: 50}, false, 50, PowerUpCost{Stardust: 250000, XLCandy: 296}},
+		{mewtwo, 1, PowerUpTarget{LeagueCap: 1500}, false, 12.5, PowerUpCost{Stardust: 15900, Candy: 26}},
+		{bulbasaur, 20, PowerUpTarget{CP: 1000}, false, 33, PowerUpCost{Stardust: 109000, Candy: 98}},
+		{bulbasaur, 20, PowerUpTarget{Level: 20}, false, 20, PowerUpCost{}},
+	}
+	for _, test := range tests {
+		plan, err := test.poke.PlanPowerUp(test.level, 15, 15, 15, test.target, test.lucky)
+		if err != nil {
+			t.Error("For", test.poke.ID, test.target, "got error", err)
+			continue
+		}
+		if plan.To != test.to || plan.Total != test.total || len(plan.Steps) != int((test.to-test.level)*2) {
+			t.Error("For", test.poke.ID, test.target, "got", plan.Print())
+		}
+		if cp := test.poke.GetCP(test.to, 15, 15, 15); plan.CP != cp {
+			t.Error("For", test.poke.ID, test.target, "expected CP", cp, "got", plan.CP)
+		}
+	}
+
+	errorTests := []struct {
+		level  float64
+		target PowerUpTarget
+		err    error
+	}{
+		{20, PowerUpTarget{}, ERR_INVALID_TARGET},
+		{20, PowerUpTarget{Level: 15}, ERR_INVALID_TARGET},
+		{20, PowerUpTarget{Level: 51}, ERR_INVALID_LEVEL},
+		{0.5, PowerUpTarget{Level: 20}, ERR_INVALID_LEVEL},
+		{20, PowerUpTarget{CP: 5000}, ERR_CP_UNREACHABLE},
+		{20, PowerUpTarget{LeagueCap: 500}, ERR_CP_UNREACHABLE},
+	}
+	for _, test := range errorTests {
+		if _, err := mewtwo.PlanPowerUp(test.level, 15, 15, 15, test.target, false); err != test.err {
+			t.Error("For", test.level, test.target, "expected", test.err, "got", err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package pogo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PvPLeagues are the CP capped leagues ranked in evolution projections
+var PvPLeagues = []int{LITTLE_LEAGUE, GREAT_LEAGUE, ULTRA_LEAGUE}
+
+// LeagueRank is the rank of an IV spread in a league. Rank is nil if the
+// pokemon is over the CP cap even at level 1.
+type LeagueRank struct {
+	CPCap int      `json:"cpCap"`
+	Rank  *PvPRank `json:"rank,omitempty"`
+}
+
+// EvolutionProjection is what a pokemon will be after evolving into one of
+// its evolutions
+type EvolutionProjection struct {
+	Pokemon *Pokemon        `json:"pokemon"`
+	Steps   []EvolutionStep `json:"-"`
+	Level   float64         `json:"level"`
+	CP      int             `json:"cp"`
+	HP      int             `json:"hp"`
+	Leagues []LeagueRank    `json:"leagues"`
+}
+
+// EvolutionRange is the possible CP and HP after evolving, for a set of
+// IV candidates
+type EvolutionRange struct {
+	Pokemon *Pokemon        `json:"pokemon"`
+	Steps   []EvolutionStep `json:"-"`
+	MinCP   int             `json:"minCP"`
+	MaxCP   int             `json:"maxCP"`
+	MinHP   int             `json:"minHP"`
+	MaxHP   int             `json:"maxHP"`
+}
+
+// evolutionPaths returns the steps to every pokemon this pokemon can evolve
+// into, directly or through later evolutions
+func (p *Pokemon) evolutionPaths() [][]EvolutionStep {
+	paths := [][]EvolutionStep{}
+	var walk func(poke *Pokemon, path []EvolutionStep, seen map[string]bool)
+	walk = func(poke *Pokemon, path []EvolutionStep, seen map[string]bool) {
+		for _, step := range poke.Evolutions() {
+			if seen[step.To.ID] {
+				continue
+			}
+			next := append(path[:len(path):len(path)], step)
+			paths = append(paths, next)
+			seen[step.To.ID] = true
+			walk(step.To, next, seen)
+			delete(seen, step.To.ID)
+		}
+	}
+	walk(p, nil, map[string]bool{p.ID: true})
+	return paths
+}
+
+// ProjectEvolution returns the CP, HP and PvP league ranks the pokemon will
+// have after evolving into each of its evolutions, keeping its level and IVs
+func (p *Pokemon) ProjectEvolution(level float64, ivAttack int, ivDefense int, ivStamina int) ([]EvolutionProjection, error) {
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	if d.getMultiplier(level) == 0 {
+		return nil, ERR_INVALID_LEVEL
+	}
+
+	projections := []EvolutionProjection{}
+	for _, path := range p.evolutionPaths() {
+		to := path[len(path)-1].To
+		projection := EvolutionProjection{
+			Pokemon: to,
+			Steps:   path,
+			Level:   level,
+			CP:      to.GetCP(level, ivAttack, ivDefense, ivStamina),
+			HP:      to.GetHP(level, ivStamina),
+		}
+		for _, cpCap := range PvPLeagues {
+			league := LeagueRank{CPCap: cpCap}
+			if ranking, err := to.GetPvPRanking(cpCap, 0); err == nil {
+				league.Rank, _ = ranking.Rank(ivAttack, ivDefense, ivStamina)
+			}
+			projection.Leagues = append(projection.Leagues, league)
+		}
+		projections = append(projections, projection)
+	}
+	return projections, nil
+}
+
+// ProjectEvolutionRange returns the possible CP and HP after evolving into
+// each evolution for a set of level and IV candidates, such as the results
+// of GetIV or IVSession.Candidates
+func (p *Pokemon) ProjectEvolutionRange(candidates []IVStat) ([]EvolutionRange, error) {
+	if len(candidates) == 0 {
+		return nil, ERR_NO_IVS
+	}
+
+	ranges := []EvolutionRange{}
+	for _, path := range p.evolutionPaths() {
+		to := path[len(path)-1].To
+		r := EvolutionRange{Pokemon: to, Steps: path}
+		for i, c := range candidates {
+			cp := to.GetCP(c.Level, c.Attack, c.Defense, c.Stamina)
+			hp := to.GetHP(c.Level, c.Stamina)
+			if i == 0 || cp < r.MinCP {
+				r.MinCP = cp
+			}
+			if i == 0 || cp > r.MaxCP {
+				r.MaxCP = cp
+			}
+			if i == 0 || hp < r.MinHP {
+				r.MinHP = hp
+			}
+			if i == 0 || hp > r.MaxHP {
+				r.MaxHP = hp
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// Candy returns the candy needed for every step of the evolution
+func (e *EvolutionProjection) Candy() (candy int) {
+	for _, step := range e.Steps {
+		candy += step.CandyCost
+	}
+	return
+}
+
+func (e *EvolutionProjection) Print() string {
+	leagues := []string{}
+	for _, league := range e.Leagues {
+		if league.Rank != nil {
+			leagues = append(leagues, fmt.Sprintf("%d: #%d", league.CPCap, league.Rank.Rank))
+		}
+	}
+	str := fmt.Sprintf("%s: CP %d, HP %d", e.Pokemon.Name, e.CP, e.HP)
+	if len(leagues) > 0 {
+		str += " (" + strings.Join(leagues, ", ") + ")"
+	}
+	return str
+}
+
+func (r *EvolutionRange) Print() string {
+	if r.MinCP == r.MaxCP {
+		return fmt.Sprintf("%s: CP %d", r.Pokemon.Name, r.MinCP)
+	}
+	return fmt.Sprintf("%s: CP %d - %d", r.Pokemon.Name, r.MinCP, r.MaxCP)
+}
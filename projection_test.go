@@ -0,0 +1,62 @@
+package pogo
+
+import (
+	"testing"
+)
+
+func TestProjectEvolution(t *testing.T) {
+	charmander, err := GetPokemon("charmander")
+	if err != nil {
+		t.Fatal("Unable to get charmander:", err)
+	}
+	charizard, _ := GetPokemon("charizard")
+
+	projections, err := charmander.ProjectEvolution(20, 10, 12, 13)
+	if err != nil {
+		t.Fatal("Unable to project charmander:", err)
+	}
+	if len(projections) != 2 || projections[0].Pokemon.ID != "charmeleon" || projections[1].Pokemon.ID != "charizard" {
+		t.Fatal("Expected charmeleon and charizard, got", projections)
+	}
+
+	e := projections[1]
+	if e.CP != charizard.GetCP(20, 10, 12, 13) || e.HP != charizard.GetHP(20, 13) || e.Candy() != 125 {
+		t.Error("Unexpected charizard projection", e.Print(), e.Candy())
+	}
+	ranking, _ := charizard.GetPvPRanking(GREAT_LEAGUE, 0)
+	rank, _ := ranking.Rank(10, 12, 13)
+	if len(e.Leagues) != 3 || e.Leagues[1].CPCap != GREAT_LEAGUE || e.Leagues[1].Rank == nil || e.Leagues[1].Rank.Rank != rank.Rank {
+		t.Error("Expected great league rank", rank.Rank, "got", e.Leagues)
+	}
+
+	if projections, err := charizard.ProjectEvolution(20, 10, 12, 13); err != nil || len(projections) != 0 {
+		t.Error("Expected no evolutions for charizard, got", projections, err)
+	}
+	if _, err := charmander.ProjectEvolution(60, 10, 12, 13); err != ERR_INVALID_LEVEL {
+		t.Error("Expected invalid level, got", err)
+	}
+}
+
+func TestProjectEvolutionRange(t *testing.T) {
+	charmander, _ := GetPokemon("charmander")
+	charizard, _ := GetPokemon("charizard")
+
+	candidates, _ := charmander.GetIV(charmander.GetCP(20, 10, 12, 13), charmander.GetHP(20, 13), 0, 0, "")
+	if len(candidates) < 2 {
+		t.Fatal("Expected several candidates, got", len(candidates))
+	}
+
+	ranges, err := charmander.ProjectEvolutionRange(candidates)
+	if err != nil || len(ranges) != 2 {
+		t.Fatal("Expected 2 ranges, got", ranges, err)
+	}
+	r := ranges[1]
+	cp := charizard.GetCP(20, 10, 12, 13)
+	if r.MinCP > cp || r.MaxCP < cp || r.MinCP == r.MaxCP {
+		t.Error("Expected range around", cp, "got", r.Print())
+	}
+
+	if _, err := charmander.ProjectEvolutionRange(nil); err != ERR_NO_IVS {
+		t.Error("Expected no IVs error, got", err)
+	}
+}
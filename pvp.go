@@ -0,0 +1,141 @@
+package pogo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Errors
+var (
+	ERR_INVALID_IV = errors.New("Invalid IVs.")
+)
+
+// League CP caps. A cap of 0 means no cap, as in Master League.
+const (
+	LITTLE_LEAGUE = 500
+	GREAT_LEAGUE  = 1500
+	ULTRA_LEAGUE  = 2500
+	MASTER_LEAGUE = 0
+)
+
+// PvPRank is how an IV spread ranks in a league, at the highest level that
+// fits under the CP cap
+type PvPRank struct {
+	Rank        int     `json:"rank"`
+	Attack      int     `json:"attack"`
+	Defense     int     `json:"defense"`
+	Stamina     int     `json:"stamina"`
+	Level       float64 `json:"level"`
+	CP          int     `json:"cp"`
+	StatProduct float64 `json:"statProduct"`
+	Percent     float64 `json:"percent"` // percentage of the rank 1 stat product
+}
+
+// PvPRanking ranks every IV spread of a pokemon in a league by stat product
+type PvPRanking struct {
+	CPCap    int       `json:"cpCap"`
+	MaxLevel float64   `json:"maxLevel"`
+	Ranks    []PvPRank `json:"ranks"`
+}
+
+// GetPvPRanking ranks all 4096 IV spreads of the pokemon under a CP cap.
+// Each spread is powered up as far as it can go without passing the cap or
+// maxLevel; use MAX_BUDDY_LEVEL to allow best buddies, or 0 for MAX_LEVEL.
+// Spreads over the cap even at level 1 are left out.
+func (p *Pokemon) GetPvPRanking(cpCap int, maxLevel float64) (*PvPRanking, error) {
+	if maxLevel == 0 {
+		maxLevel = MAX_LEVEL
+	}
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	if d.getMultiplier(maxLevel) == 0 {
+		return nil, ERR_INVALID_LEVEL
+	}
+
+	levels := []float64{}
+	for _, l := range d.getLevels() {
+		if l <= maxLevel {
+			levels = append(levels, l)
+		}
+	}
+
+	ranking := &PvPRanking{CPCap: cpCap, MaxLevel: maxLevel, Ranks: []PvPRank{}}
+	for a := 0; a <= 15; a++ {
+		for df := 0; df <= 15; df++ {
+			for s := 0; s <= 15; s++ {
+				// CP only goes up with level, so find the first level over the cap
+				i := len(levels)
+				if cpCap > 0 {
+					i = sort.Search(len(levels), func(i int) bool {
+						return p.GetCP(levels[i], a, df, s) > cpCap
+					})
+				}
+				if i == 0 {
+					continue
+				}
+				stats := p.GetStats(levels[i-1], a, df, s, false)
+				ranking.Ranks = append(ranking.Ranks, PvPRank{
+					Attack:      a,
+					Defense:     df,
+					Stamina:     s,
+					Level:       stats.Level,
+					CP:          stats.CP,
+					StatProduct: stats.StatProduct(),
+				})
+			}
+		}
+	}
+	if len(ranking.Ranks) == 0 {
+		return nil, ERR_CP_UNREACHABLE
+	}
+
+	sort.SliceStable(ranking.Ranks, func(i, j int) bool {
+		return ranking.Ranks[i].StatProduct > ranking.Ranks[j].StatProduct
+	})
+	best := ranking.Ranks[0].StatProduct
+	for i := range ranking.Ranks {
+		r := &ranking.Ranks[i]
+		r.Rank = i + 1
+		if i > 0 && r.StatProduct == ranking.Ranks[i-1].StatProduct {
+			r.Rank = ranking.Ranks[i-1].Rank
+		}
+		r.Percent = r.StatProduct / best * 100
+	}
+	return ranking, nil
+}
+
+// Rank returns the rank of an IV spread
+func (r *PvPRanking) Rank(ivAttack int, ivDefense int, ivStamina int) (*PvPRank, error) {
+	for _, rank := range r.Ranks {
+		if rank.Attack == ivAttack && rank.Defense == ivDefense && rank.Stamina == ivStamina {
+			return &rank, nil
+		}
+	}
+	return nil, ERR_INVALID_IV
+}
+
+// Top returns the n best IV spreads
+func (r *PvPRanking) Top(n int) []PvPRank {
+	if n > len(r.Ranks) {
+		n = len(r.Ranks)
+	}
+	return r.Ranks[:n]
+}
+
+func (r *PvPRank) PrintRankRow() string {
+	return fmt.Sprintf("|%4d| %2d | %2d | %2d |%4.1f|%4d|%6.2f%%", r.Rank, r.Attack, r.Defense, r.Stamina, r.Level, r.CP, r.Percent)
+}
+
+func (r *PvPRanking) Print(n int) string {
+	str := "|Rank| At | Df | St |Lvl | CP |   %%\n"
+	str += "|----|----|----|----|----|----|-------\n"
+	rows := []string{}
+	for _, rank := range r.Top(n) {
+		rows = append(rows, rank.PrintRankRow())
+	}
+	return str + strings.Join(rows, "\n")
+}
@@ -0,0 +1,55 @@
+package pogo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetPvPRanking(t *testing.T) {
+	azumarill, err := GetPokemon("azumarill")
+	if err != nil {
+		t.Fatal("Unable to get azumarill:", err)
+	}
+
+	ranking, err := azumarill.GetPvPRanking(GREAT_LEAGUE, 0)
+	if err != nil {
+		t.Fatal("Unable to rank azumarill:", err)
+	}
+	if len(ranking.Ranks) != 4096 {
+		t.Error("Expected 4096 IV spreads, got", len(ranking.Ranks))
+	}
+
+	top := ranking.Top(1)[0]
+	if top.Attack != 0 || top.Defense != 15 || top.Stamina != 15 || top.Level != 45.5 || top.CP != 1499 || top.Percent != 100 {
+		t.Error("Expected rank 1 0/15/15 at level 45.5, got", top.PrintRankRow())
+	}
+
+	hundo, err := ranking.Rank(15, 15, 15)
+	if err != nil || hundo.Rank != 2550 || hundo.Level != 36 || math.Abs(hundo.Percent-93.73) > 0.01 {
+		t.Error("Expected 15/15/15 at rank 2550, got", hundo, err)
+	}
+	if _, err := ranking.Rank(16, 0, 0); err != ERR_INVALID_IV {
+		t.Error("Expected invalid IVs, got", err)
+	}
+
+	// Spreads with the same stat product share a rank
+	medicham, _ := GetPokemon("medicham")
+	ranking, err = medicham.GetPvPRanking(GREAT_LEAGUE, 0)
+	if err != nil {
+		t.Fatal("Unable to rank medicham:", err)
+	}
+	if top := ranking.Top(2); top[0].Rank != 1 || top[1].Rank != 1 || top[0].Level != 50 {
+		t.Error("Expected two rank 1 spreads at level 50, got", top)
+	}
+	if buddy, err := medicham.GetPvPRanking(GREAT_LEAGUE, MAX_BUDDY_LEVEL); err != nil || buddy.Ranks[0].Level <= 50 {
+		t.Error("Expected best buddy medicham above level 50, got", buddy.Ranks[0], err)
+	}
+
+	mewtwo, _ := GetPokemon("mewtwo")
+	if _, err := mewtwo.GetPvPRanking(10, 0); err != ERR_CP_UNREACHABLE {
+		t.Error("Expected unreachable CP cap, got", err)
+	}
+	if ranking, err := mewtwo.GetPvPRanking(MASTER_LEAGUE, 0); err != nil || ranking.Ranks[0].Level != 50 || ranking.Ranks[0].Attack != 15 {
+		t.Error("Expected 15/15/15 level 50 in master league, got", ranking.Ranks[0], err)
+	}
+}
@@ -0,0 +1,79 @@
+package pogo
+
+import (
+	"fmt"
+)
+
+// Stats are the stats of a single pokemon: its species' base stats combined
+// with its IVs, level, variant and best buddy boost
+type Stats struct {
+	Pokemon   *Pokemon `json:"-"`
+	Level     float64  `json:"level"`
+	IVAttack  int      `json:"ivAttack"`
+	IVDefense int      `json:"ivDefense"`
+	IVStamina int      `json:"ivStamina"`
+	BestBuddy bool     `json:"bestBuddy,omitempty"`
+
+	// CPMultiplier is the multiplier for the level, including the best
+	// buddy boost
+	CPMultiplier float64 `json:"cpMultiplier"`
+
+	// Attack and Defense are the stats used in battle, including the shadow
+	// bonus and penalty
+	Attack  float64 `json:"attack"`
+	Defense float64 `json:"defense"`
+	Stamina float64 `json:"stamina"`
+	HP      int     `json:"hp"`
+	CP      int     `json:"cp"`
+}
+
+// GetStats returns the stats of the pokemon at a level with its IVs. A best
+// buddy battles BEST_BUDDY_BONUS levels higher.
+func (p *Pokemon) GetStats(level float64, ivAttack int, ivDefense int, ivStamina int, bestBuddy bool) Stats {
+	effective := level
+	if bestBuddy {
+		effective += BEST_BUDDY_BONUS
+	}
+	d, _ := p.getPokedex()
+	cpm := d.getMultiplier(effective)
+
+	attack := float64(p.Stats.BaseAttack + ivAttack)
+	defense := float64(p.Stats.BaseDefense + ivDefense)
+	stamina := float64(p.Stats.BaseStamina + ivStamina)
+
+	return Stats{
+		Pokemon:      p,
+		Level:        level,
+		IVAttack:     ivAttack,
+		IVDefense:    ivDefense,
+		IVStamina:    ivStamina,
+		BestBuddy:    bestBuddy,
+		CPMultiplier: cpm,
+		Attack:       attack * cpm * p.Variant.AttackModifier(),
+		Defense:      defense * cpm * p.Variant.DefenseModifier(),
+		Stamina:      stamina * cpm,
+		HP:           calculateHP(stamina, cpm),
+		CP:           calculateCP(attack, defense, stamina, cpm),
+	}
+}
+
+// StatProduct returns attack times defense times HP, the measure used to
+// rank IVs for PvP
+func (s Stats) StatProduct() float64 {
+	return s.Attack * s.Defense * float64(s.HP)
+}
+
+// GetDamage returns the damage a move used by this pokemon does to defender
+func (s Stats) GetDamage(move *Move, defender Stats) int {
+	return GetDamage(move, s.Pokemon, s.Attack, defender.Pokemon, defender.Defense)
+}
+
+// GetBoostedDamage returns the damage a move used by this pokemon does to
+// defender with an attack boost, see GetMegaBoost
+func (s Stats) GetBoostedDamage(move *Move, defender Stats, boost float64) int {
+	return GetBoostedDamage(move, s.Pokemon, s.Attack, defender.Pokemon, defender.Defense, boost)
+}
+
+func (s Stats) Print() string {
+	return fmt.Sprintf("Level %v %d/%d/%d: CP %d, HP %d, Attack %.1f, Defense %.1f", s.Level, s.IVAttack, s.IVDefense, s.IVStamina, s.CP, s.HP, s.Attack, s.Defense)
+}
@@ -0,0 +1,43 @@
+package pogo
+
+import (
+	"testing"
+)
+
+func TestGetStats(t *testing.T) {
+	mewtwo, err := GetPokemon("mewtwo")
+	if err != nil {
+		t.Fatal("Unable to get mewtwo:", err)
+	}
+
+	stats := mewtwo.GetStats(40, 15, 15, 15, false)
+	if stats.CP != 4178 || stats.HP != mewtwo.GetHP(40, 15) || stats.Attack != (300+15)*0.79030001 {
+		t.Error("Unexpected level 40 stats", stats.Print())
+	}
+
+	buddy := mewtwo.GetStats(40, 15, 15, 15, true)
+	if buddy.Level != 40 || buddy.CP != mewtwo.GetCP(41, 15, 15, 15) || buddy.CPMultiplier != mewtwo.GetStats(41, 15, 15, 15, false).CPMultiplier {
+		t.Error("Expected best buddy to battle at level 41, got", buddy.Print())
+	}
+
+	ranking, _ := mewtwo.GetPvPRanking(ULTRA_LEAGUE, 0)
+	rank, _ := ranking.Rank(15, 15, 15)
+	if sp := mewtwo.GetStats(rank.Level, 15, 15, 15, false).StatProduct(); sp != rank.StatProduct {
+		t.Error("Expected stat product", rank.StatProduct, "got", sp)
+	}
+
+	shadow, _ := GetPokemon("bulbasaur-shadow")
+	normal, _ := GetPokemon("bulbasaur")
+	s, n := shadow.GetStats(20, 10, 10, 10, false), normal.GetStats(20, 10, 10, 10, false)
+	if s.CP != n.CP || s.Attack != n.Attack*SHADOW_ATTACK_BONUS || s.Defense != n.Defense*SHADOW_DEFENSE_BONUS {
+		t.Error("Expected shadow battle stats with the same CP, got", s.Print(), n.Print())
+	}
+
+	move, err := GetMove("vine whip fast")
+	if err != nil {
+		t.Fatal("Unable to get vine whip:", err)
+	}
+	if damage := n.GetDamage(move, stats); damage != GetDamage(move, normal, n.Attack, mewtwo, stats.Defense) {
+		t.Error("Expected matching damage, got", damage)
+	}
+}
@@ -0,0 +1,164 @@
+package pogo
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Store holds the current Pokedex for a long running process and swaps in a
+// new one when the game data is reloaded. A Pokedex is never changed once
+// loaded, so anything holding one keeps a consistent snapshot across reloads.
+type Store struct {
+	opts    LoadOptions
+	current atomic.Value
+	lock    sync.Mutex
+}
+
+// NewStore loads the game data described by opts into a new Store
+func NewStore(opts LoadOptions) (*Store, error) {
+	d, err := loadChecked(opts)
+	if err != nil {
+		return nil, err
+	}
+	return newStore(opts, d), nil
+}
+
+func newStore(opts LoadOptions, d *Pokedex) *Store {
+	s := &Store{opts: opts}
+	s.current.Store(d)
+	return s
+}
+
+// Pokedex returns the current snapshot of the game data
+func (s *Store) Pokedex() *Pokedex {
+	return s.current.Load().(*Pokedex)
+}
+
+// Reload reads the game data again and swaps it in if it is valid. On error
+// the current data is kept.
+func (s *Store) Reload() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	d, err := loadChecked(s.opts)
+	if err != nil {
+		return err
+	}
+	s.current.Store(d)
+	return nil
+}
+
+// MIN_WATCH_INTERVAL is the shortest interval Watch checks the data files at
+const MIN_WATCH_INTERVAL = 100 * time.Millisecond
+
+// Watch checks the data files every interval, at least MIN_WATCH_INTERVAL,
+// and reloads when any of them has changed, even to an older version.
+// Reload errors are logged. Call stop to end the watch.
+func (s *Store) Watch(interval time.Duration) (stop func()) {
+	if interval < MIN_WATCH_INTERVAL {
+		interval = MIN_WATCH_INTERVAL
+	}
+	last := s.fileState()
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if state := s.fileState(); state != last {
+					last = state
+					if err := s.Reload(); err != nil {
+						log.Println("Unable to reload game data:", err.Error())
+					}
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// ReloadOnSignal reloads the game data whenever one of sigs is received,
+// such as syscall.SIGHUP. Reload errors are logged. Call stop to end it.
+func (s *Store) ReloadOnSignal(sigs ...os.Signal) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, sigs...)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+				if err := s.Reload(); err != nil {
+					log.Println("Unable to reload game data:", err.Error())
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// fileState returns the modification time and size of each data file, so
+// any change to them, including going back to an older file, changes it
+func (s *Store) fileState() string {
+	state := []string{}
+	stat := func(info fs.FileInfo, err error) {
+		if err == nil {
+			state = append(state, fmt.Sprintf("%s %d %d", info.Name(), info.ModTime().UnixNano(), info.Size()))
+		}
+	}
+
+	switch {
+	case s.opts.GameMaster != "":
+		stat(os.Stat(s.opts.GameMaster))
+	case s.opts.FS != nil:
+		for _, name := range []string{POKEMON_FILE, MOVES_FILE, TYPES_FILE, LEVELS_FILE} {
+			stat(fs.Stat(s.opts.FS, strings.TrimPrefix(name, "/")))
+		}
+	case s.opts.Dir != "":
+		for _, name := range []string{POKEMON_FILE, MOVES_FILE, TYPES_FILE, LEVELS_FILE} {
+			stat(os.Stat(filepath.Join(s.opts.Dir, name)))
+		}
+	}
+	return strings.Join(state, "\n")
+}
+
+// loadChecked loads the game data and makes sure it is usable, rejecting it
+// if Validate finds pokemon without base stats or with unknown types
+func loadChecked(opts LoadOptions) (*Pokedex, error) {
+	d, err := Load(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, problem := range d.Validate() {
+		switch problem.Kind {
+		case PROBLEM_NO_STATS, PROBLEM_UNKNOWN_TYPE:
+			return nil, problem
+		}
+	}
+	return d, nil
+}
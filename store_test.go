@@ -0,0 +1,116 @@
+package pogo
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testStoreFS(name string) fstest.MapFS {
+	return fstest.MapFS{
+		"type.json":    {Data: []byte(`[{"id":"POKEMON_TYPE_NORMAL","name":"Normal","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1}]}]`)},
+		"move.json":    {Data: []byte(`[{"id":"TACKLE_FAST","name":"Tackle Fast","power":5,"pokemonType":{"id":"POKEMON_TYPE_NORMAL","name":"Normal"}}]`)},
+		"pokemon.json": {Data: []byte(`[{"id":"PIDGEY","name":"` + name + `","dex":16,"types":[{"id":"POKEMON_TYPE_NORMAL","name":"Normal"}],"stats":{"baseAttack":85,"baseDefense":73,"baseStamina":120}}]`)},
+	}
+}
+
+func TestStoreReload(t *testing.T) {
+	fsys := testStoreFS("Pidgey")
+	s, err := NewStore(LoadOptions{FS: fsys})
+	if err != nil {
+		t.Fatal("Unable to create store:", err)
+	}
+	before := s.Pokedex()
+
+	// Bad data is rejected and the current data kept
+	fsys["pokemon.json"] = &fstest.MapFile{Data: []byte(`[{"id":"PIDGEY","name":"Pidgey","types":[{"id":"POKEMON_TYPE_FIRE"}]}]`)}
+	if err := s.Reload(); err == nil {
+		t.Error("Expected reload of bad data to fail")
+	}
+	if s.Pokedex() != before {
+		t.Error("Expected bad data to keep the current pokedex")
+	}
+
+	fsys["pokemon.json"] = testStoreFS("Pidgey Reloaded")["pokemon.json"]
+	if err := s.Reload(); err != nil {
+		t.Fatal("Unable to reload:", err)
+	}
+	if p, err := s.Pokedex().GetPokemon("pidgey"); err != nil || p.Name != "Pidgey Reloaded" {
+		t.Error("Expected reloaded pidgey, got", p, err)
+	}
+
+	// Snapshots taken before the reload are unchanged
+	if p, err := before.GetPokemon("pidgey"); err != nil || p.Name != "Pidgey" {
+		t.Error("Expected snapshot pidgey, got", p, err)
+	}
+}
+
+// writeStoreDir writes the test data files into dir
+func writeStoreDir(t *testing.T, dir string, name string, modTime time.Time) {
+	for file, data := range testStoreFS(name) {
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, data.Data, 0644); err != nil {
+			t.Fatal("Unable to write", file, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal("Unable to set the time of", file, err)
+		}
+	}
+}
+
+// waitForPokemon waits for the store to have pidgey with the name
+func waitForPokemon(t *testing.T, s *Store, name string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if p, err := s.Pokedex().GetPokemon("pidgey"); err == nil && p.Name == name {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Expected the store to reload", name)
+}
+
+func TestStoreWatch(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeStoreDir(t, dir, "Pidgey", now)
+	s, err := NewStore(LoadOptions{Dir: dir})
+	if err != nil {
+		t.Fatal("Unable to create store:", err)
+	}
+
+	// An interval of 0 is raised to the minimum rather than panicking
+	stop := s.Watch(0)
+	defer stop()
+
+	writeStoreDir(t, dir, "Pidgey Updated", now.Add(time.Minute))
+	waitForPokemon(t, s, "Pidgey Updated")
+
+	// Rolling back to a file with an older time is picked up too
+	writeStoreDir(t, dir, "Pidgey", now.Add(-time.Hour))
+	waitForPokemon(t, s, "Pidgey")
+}
+
+func TestStoreReloadOnSignal(t *testing.T) {
+	dir := t.TempDir()
+	writeStoreDir(t, dir, "Pidgey", time.Now())
+	s, err := NewStore(LoadOptions{Dir: dir})
+	if err != nil {
+		t.Fatal("Unable to create store:", err)
+	}
+	stop := s.ReloadOnSignal(syscall.SIGHUP)
+	defer stop()
+
+	writeStoreDir(t, dir, "Pidgey Signalled", time.Now())
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal("Unable to find the test process:", err)
+	}
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Fatal("Unable to signal:", err)
+	}
+	waitForPokemon(t, s, "Pidgey Signalled")
+}
@@ -0,0 +1,189 @@
+package pogo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors
+var (
+	ERR_EMPTY_TEAM    = errors.New("Team has no pokemon.")
+	ERR_MIXED_POKEDEX = errors.New("Team members are from different Pokedexes.")
+)
+
+// SHARED_WEAKNESS is how many team members have to be weak to a type for it
+// to count as a shared weakness
+const SHARED_WEAKNESS = 2
+
+// TeamMember is a pokemon on a team and, optionally, its moves
+type TeamMember struct {
+	Pokemon *Pokemon `json:"pokemon"`
+	Moves   MoveList `json:"moves,omitempty"`
+}
+
+// TypeCoverage is how a team fares against one type
+type TypeCoverage struct {
+	Type    PokemonType `json:"type"`
+	Weak    []string    `json:"weak"`    // members that take super effective damage from the type
+	Resist  []string    `json:"resist"`  // members that resist the type
+	Covered []string    `json:"covered"` // members that hit the type super effectively
+
+	// Defense adds up the members' tiers against the type, so a double
+	// resist counts 2 and a double weakness -2. Score adds one for each
+	// member that covers it.
+	Defense int `json:"defense"`
+	Score   int `json:"score"`
+}
+
+// TeamCoverage is the type coverage of a team, for every type in the order of TypeIDs
+type TeamCoverage struct {
+	Members []TeamMember   `json:"members"`
+	Types   []TypeCoverage `json:"types"`
+}
+
+// GetTeamMember returns a team member by pokemon name and move names
+func GetTeamMember(pokemonName string, moveNames ...string) (TeamMember, error) {
+	p, err := GetPokemon(pokemonName)
+	if err != nil {
+		return TeamMember{}, err
+	}
+	member := TeamMember{Pokemon: p}
+	for _, name := range moveNames {
+		m, err := p.pokedex.GetMove(name)
+		if err != nil {
+			return TeamMember{}, err
+		}
+		member.Moves = append(member.Moves, m)
+	}
+	return member, nil
+}
+
+// attackTypes returns the types the member attacks with: the types of its
+// moves, or its own types if no moves were given
+func (m TeamMember) attackTypes() []string {
+	types := []string{}
+	seen := map[string]bool{}
+	if len(m.Moves) > 0 {
+		for _, move := range m.Moves {
+			if !seen[move.Type.ID] {
+				seen[move.Type.ID] = true
+				types = append(types, move.Type.ID)
+			}
+		}
+		return types
+	}
+	for _, t := range m.Pokemon.Types {
+		types = append(types, t.ID)
+	}
+	return types
+}
+
+// AnalyzeTeam returns the type coverage of a team. Every member has to come
+// from the same Pokedex.
+func AnalyzeTeam(members ...TeamMember) (*TeamCoverage, error) {
+	if len(members) == 0 {
+		return nil, ERR_EMPTY_TEAM
+	}
+	for _, m := range members {
+		if m.Pokemon == nil {
+			return nil, ERR_NOT_FOUND
+		}
+	}
+
+	d, err := members[0].Pokemon.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members[1:] {
+		if md, err := m.Pokemon.getPokedex(); err != nil {
+			return nil, err
+		} else if md != d {
+			return nil, ERR_MIXED_POKEDEX
+		}
+	}
+
+	coverage := &TeamCoverage{Members: members}
+	for _, id := range d.typeIDs() {
+		c := TypeCoverage{
+			Type:    PokemonType{ID: id, Name: d.typeMap[id].Name},
+			Weak:    []string{},
+			Resist:  []string{},
+			Covered: []string{},
+		}
+		for _, m := range members {
+			scalar := 1.0
+			for _, t := range m.Pokemon.Types {
+				scalar *= d.getScalar(id, t.ID)
+			}
+			tier := getTier(scalar)
+			if tier > TIER_NEUTRAL {
+				c.Weak = append(c.Weak, m.Pokemon.Name)
+			} else if tier < TIER_NEUTRAL {
+				c.Resist = append(c.Resist, m.Pokemon.Name)
+			}
+			c.Defense -= int(tier)
+
+			for _, at := range m.attackTypes() {
+				if getTier(d.getScalar(at, id)) > TIER_NEUTRAL {
+					c.Covered = append(c.Covered, m.Pokemon.Name)
+					break
+				}
+			}
+		}
+		c.Score = c.Defense + len(c.Covered)
+		coverage.Types = append(coverage.Types, c)
+	}
+	return coverage, nil
+}
+
+// SharedWeaknesses returns the attacking types at least SHARED_WEAKNESS
+// members are weak to
+func (c *TeamCoverage) SharedWeaknesses() []TypeCoverage {
+	types := []TypeCoverage{}
+	for _, t := range c.Types {
+		if len(t.Weak) >= SHARED_WEAKNESS {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// Uncovered returns the defending types none of the team's moves hit super
+// effectively
+func (c *TeamCoverage) Uncovered() []TypeCoverage {
+	types := []TypeCoverage{}
+	for _, t := range c.Types {
+		if len(t.Covered) == 0 {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+func (c *TeamCoverage) Print() string {
+	shared := []string{}
+	for _, t := range c.SharedWeaknesses() {
+		shared = append(shared, fmt.Sprintf("%s (%s)", t.Type.Name, strings.Join(t.Weak, ", ")))
+	}
+	uncovered := []string{}
+	for _, t := range c.Uncovered() {
+		uncovered = append(uncovered, t.Type.Name)
+	}
+	if len(shared) == 0 {
+		shared = append(shared, "None")
+	}
+	if len(uncovered) == 0 {
+		uncovered = append(uncovered, "None")
+	}
+
+	str := fmt.Sprintf("**Shared weaknesses:** %s\n", strings.Join(shared, ", "))
+	str += fmt.Sprintf("**Not covered:** %s\n", strings.Join(uncovered, ", "))
+	str += "|Type    |Weak|Res |Hit |Net |\n"
+	str += "|--------|----|----|----|----|\n"
+	rows := []string{}
+	for _, t := range c.Types {
+		rows = append(rows, fmt.Sprintf("|%-8s|%4d|%4d|%4d|%4d|", t.Type.Name, len(t.Weak), len(t.Resist), len(t.Covered), t.Score))
+	}
+	return str + strings.Join(rows, "\n")
+}
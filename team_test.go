@@ -0,0 +1,80 @@
+package pogo
+
+import (
+	"strings"
+	"testing"
+)
+
+func findCoverage(types []TypeCoverage, name string) *TypeCoverage {
+	for _, t := range types {
+		if t.Type.Name == name {
+			return &t
+		}
+	}
+	return nil
+}
+
+func TestAnalyzeTeam(t *testing.T) {
+	gyarados, err := GetTeamMember("gyarados", "waterfall fast", "crunch")
+	if err != nil {
+		t.Fatal("Unable to get gyarados:", err)
+	}
+	azumarill, err := GetTeamMember("azumarill", "bubble fast", "play rough")
+	if err != nil {
+		t.Fatal("Unable to get azumarill:", err)
+	}
+
+	team, err := AnalyzeTeam(gyarados, azumarill)
+	if err != nil {
+		t.Fatal("Unable to analyze team:", err)
+	}
+	if len(team.Types) != NUM_TYPES {
+		t.Error("Expected every type, got", len(team.Types))
+	}
+
+	shared := team.SharedWeaknesses()
+	if len(shared) != 1 || shared[0].Type.Name != "Electric" || strings.Join(shared[0].Weak, ", ") != "Gyarados, Azumarill" {
+		t.Error("Expected a shared electric weakness, got", shared)
+	}
+	if electric := findCoverage(team.Types, "Electric"); electric.Defense != -3 || electric.Score != -3 {
+		t.Error("Expected electric defense and score of -3, got", electric.Defense, electric.Score)
+	}
+
+	uncovered := team.Uncovered()
+	if findCoverage(uncovered, "Steel") == nil || findCoverage(uncovered, "Rock") != nil || findCoverage(uncovered, "Dragon") != nil {
+		t.Error("Expected steel uncovered and rock and dragon covered, got", uncovered)
+	}
+	if dragon := findCoverage(team.Types, "Dragon"); len(dragon.Covered) != 1 || dragon.Covered[0] != "Azumarill" {
+		t.Error("Expected azumarill to cover dragon, got", dragon.Covered)
+	}
+
+	// Members have to come from the same Pokedex
+	other, err := NewPokedex(EmbeddedFS())
+	if err != nil {
+		t.Fatal("Unable to load pokedex:", err)
+	}
+	otherAzumarill, _ := other.GetPokemon("azumarill")
+	if _, err := AnalyzeTeam(gyarados, TeamMember{Pokemon: otherAzumarill}); err != ERR_MIXED_POKEDEX {
+		t.Error("Expected mixed pokedex error, got", err)
+	}
+
+	// Without moves the pokemon's own types are used
+	gyarados.Moves = nil
+	team, err = AnalyzeTeam(gyarados)
+	if err != nil {
+		t.Fatal("Unable to analyze team:", err)
+	}
+	if grass := findCoverage(team.Types, "Grass"); len(grass.Covered) != 1 {
+		t.Error("Expected gyarados to cover grass with flying, got", grass.Covered)
+	}
+	if !strings.HasPrefix(team.Print(), "**Shared weaknesses:** None\n") {
+		t.Error("Expected no shared weaknesses, got", team.Print())
+	}
+
+	if _, err := AnalyzeTeam(); err != ERR_EMPTY_TEAM {
+		t.Error("Expected empty team error, got", err)
+	}
+	if _, err := GetTeamMember("gyarados", "splash dance"); err != ERR_MOVE_NOT_FOUND {
+		t.Error("Expected move not found, got", err)
+	}
+}
@@ -1,16 +1,12 @@
 package pogo
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
-	"io/ioutil"
+	"math"
+	"sort"
 	"strings"
 )
 
-var typeMap = map[string]Type{}
-var typeToID = map[string]string{}
-
 var (
 	ERR_TYPE_NOT_FOUND = errors.New("Type not found.")
 )
@@ -21,6 +17,8 @@ type Type struct {
 	Damage    []*TypeDamage `json:"damage"`
 	Thumbnail string
 	TypeRelations
+
+	pokedex *Pokedex
 }
 
 type TypeList []*PokemonType
@@ -42,16 +40,77 @@ type TypeRelations struct {
 	Resistance     TypeRelation
 }
 
-type TypeRelation []string
+// TypeRelation lists the types in one relation, with the strongest effects
+// first and otherwise in the order of TypeIDs
+type TypeRelation []TypeEffect
+
+// TypeEffect is how strongly one type hits another
+type TypeEffect struct {
+	Type       PokemonType `json:"type"`
+	Multiplier float64     `json:"multiplier"`
+	Tier       TypeTier    `json:"tier"`
+}
+
+// TypeTier counts the super effective or not very effective match ups behind
+// a multiplier, from the defender's point of view
+type TypeTier int
+
+// Type tiers
+const (
+	TIER_TRIPLE_RESIST TypeTier = -3
+	TIER_DOUBLE_RESIST TypeTier = -2
+	TIER_RESIST        TypeTier = -1
+	TIER_NEUTRAL       TypeTier = 0
+	TIER_WEAK          TypeTier = 1
+	TIER_DOUBLE_WEAK   TypeTier = 2
+)
 
+// SUPER_EFFECTIVE is the multiplier of a single super effective match up.
+// Not very effective is its inverse, 0.625.
+const SUPER_EFFECTIVE = 1.6
+
+var tierNames = map[TypeTier]string{
+	TIER_TRIPLE_RESIST: "triple resist",
+	TIER_DOUBLE_RESIST: "double resist",
+	TIER_RESIST:        "resist",
+	TIER_NEUTRAL:       "neutral",
+	TIER_WEAK:          "weak",
+	TIER_DOUBLE_WEAK:   "double weak",
+}
+
+func (t TypeTier) String() string {
+	return tierNames[t]
+}
+
+// getTier returns the tier of a multiplier, such as TIER_DOUBLE_RESIST for 0.390625
+func getTier(multiplier float64) TypeTier {
+	if multiplier <= 0 {
+		return TIER_TRIPLE_RESIST
+	}
+	tier := TypeTier(round(math.Log(multiplier) / math.Log(SUPER_EFFECTIVE)))
+	if tier < TIER_TRIPLE_RESIST {
+		return TIER_TRIPLE_RESIST
+	}
+	if tier > TIER_DOUBLE_WEAK {
+		return TIER_DOUBLE_WEAK
+	}
+	return tier
+}
+
+func (e TypeEffect) Print() string {
+	if e.Tier >= TIER_DOUBLE_WEAK || e.Tier <= TIER_DOUBLE_RESIST {
+		return e.Type.Name + "(x2)"
+	}
+	return e.Type.Name
+}
+
+// GetType returns a Type resource from the default Pokedex
 func GetType(t string) (*Type, error) {
-	t = strings.ToLower(t)
-	if ty, ok := typeMap[typeToID[t]]; ok {
-		ty.GetTypeEffects()
-		return &ty, nil
-	} else {
-		return &Type{}, ERR_TYPE_NOT_FOUND
+	d, err := DefaultPokedex()
+	if err != nil {
+		return &Type{}, err
 	}
+	return d.GetType(t)
 }
 
 func (typeList TypeList) Print() string {
@@ -61,63 +120,67 @@ func (typeList TypeList) Print() string {
 	}
 	return strings.Join(types, ", ")
 }
+
+// GetTypeEffects fills in the types the type is strong and weak against,
+// and the types it is weak to and resists
 func (t *Type) GetTypeEffects() {
 	if t.SuperEffective.Len() != 0 {
 		return
 	}
 
-	typeRelations := make(map[string]map[string]float64)
-	typeRelations["attack"] = GetAttackTypeScalars(t.ID)
-	typeRelations["defense"] = GetDefenseTypeScalars(t.ID)
-
-	//Attack
-	for ty, sc := range typeRelations["attack"] {
-		if sc > 1.9 {
-			t.SuperEffective = append(t.SuperEffective, ty+"(x2)")
-		} else if sc >= 1.4 {
-			t.SuperEffective = append(t.SuperEffective, ty)
-		} else if sc <= .6 {
-			t.NotEffective = append(t.NotEffective, ty+"(x2)")
-		} else if sc <= .8 {
-			t.NotEffective = append(t.NotEffective, ty)
-		}
+	d := t.pokedex
+	if d == nil {
+		d, _ = DefaultPokedex()
 	}
 
-	//Defense
-	for ty, sc := range typeRelations["defense"] {
-		if sc > 1.9 {
-			t.Weakness = append(t.Weakness, ty+"(x2)")
-		} else if sc >= 1.4 {
-			t.Weakness = append(t.Weakness, ty)
-		} else if sc <= .6 {
-			t.Resistance = append(t.Resistance, ty+"(x2)")
-		} else if sc <= .8 {
-			t.Resistance = append(t.Resistance, ty)
-		}
+	t.TypeRelations = d.typeRelations(func(id string) float64 {
+		return d.getScalar(t.ID, id)
+	}, func(id string) float64 {
+		return d.getScalar(id, t.ID)
+	})
+}
+
+// GetAttackTypeScalars returns the damage scalars of a type attacking each
+// other type in the default Pokedex
+func GetAttackTypeScalars(id string) (map[string]float64, error) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		return nil, err
 	}
+	return d.GetAttackTypeScalars(id), nil
+}
 
-	return
+// GetDefenseTypeScalars returns the damage scalars of each type attacking
+// a type in the default Pokedex
+func GetDefenseTypeScalars(id string) (map[string]float64, error) {
+	d, err := DefaultPokedex()
+	if err != nil {
+		return nil, err
+	}
+	return d.GetDefenseTypeScalars(id), nil
 }
 
-func GetAttackTypeScalars(id string) map[string]float64 {
+// GetAttackTypeScalars returns the damage scalars of a type attacking each other type
+func (d *Pokedex) GetAttackTypeScalars(id string) map[string]float64 {
 	typeScalars := map[string]float64{}
-	if ty, ok := typeMap[id]; ok {
+	if ty, ok := d.typeMap[id]; ok {
 
 		for _, damage := range ty.Damage {
-			typeScalars[typeMap[damage.ID].Name] = damage.Scalar
+			typeScalars[d.typeMap[damage.ID].Name] = damage.Scalar
 		}
 	}
 
 	return typeScalars
 }
 
-func GetDefenseTypeScalars(id string) map[string]float64 {
-	if _, ok := typeMap[id]; !ok {
+// GetDefenseTypeScalars returns the damage scalars of each type attacking a type
+func (d *Pokedex) GetDefenseTypeScalars(id string) map[string]float64 {
+	if _, ok := d.typeMap[id]; !ok {
 		return nil
 	}
 
 	typeScalars := map[string]float64{}
-	for _, ty := range typeMap {
+	for _, ty := range d.typeMap {
 		for _, typeDamage := range ty.Damage {
 			if typeDamage.ID == id {
 				typeScalars[ty.Name] = typeDamage.Scalar
@@ -128,27 +191,56 @@ func GetDefenseTypeScalars(id string) map[string]float64 {
 	return typeScalars
 }
 
-func init() {
-	typeMap = make(map[string]Type)
-
-	//Types
-	file, err := ioutil.ReadFile(JSON_LOCATION + TYPES_FILE)
-	if err != nil {
-		fmt.Println(err.Error())
-		return
+// typeIDs returns the ids of the types in the Pokedex in the order of
+// TypeIDs, followed by any others sorted by id
+func (d *Pokedex) typeIDs() []string {
+	ids := []string{}
+	if d == nil {
+		return ids
 	}
+	for _, id := range TypeIDs {
+		if _, ok := d.typeMap[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	others := []string{}
+	for id := range d.typeMap {
+		if _, ok := typeIndex[id]; !ok {
+			others = append(others, id)
+		}
+	}
+	sort.Strings(others)
+	return append(ids, others...)
+}
 
-	typeList := []Type{}
-	err = json.Unmarshal(file, &typeList)
-	if err != nil {
-		fmt.Println(err.Error())
-		return
+// typeRelations sorts every type into relations by the multiplier of
+// attacking it and of being attacked by it
+func (d *Pokedex) typeRelations(attack func(id string) float64, defense func(id string) float64) TypeRelations {
+	relations := TypeRelations{}
+	for _, id := range d.typeIDs() {
+		ty := PokemonType{ID: id, Name: d.typeMap[id].Name}
+
+		a := TypeEffect{Type: ty, Multiplier: attack(id)}
+		a.Tier = getTier(a.Multiplier)
+		if a.Tier > TIER_NEUTRAL {
+			relations.SuperEffective = append(relations.SuperEffective, a)
+		} else if a.Tier < TIER_NEUTRAL {
+			relations.NotEffective = append(relations.NotEffective, a)
+		}
+
+		df := TypeEffect{Type: ty, Multiplier: defense(id)}
+		df.Tier = getTier(df.Multiplier)
+		if df.Tier > TIER_NEUTRAL {
+			relations.Weakness = append(relations.Weakness, df)
+		} else if df.Tier < TIER_NEUTRAL {
+			relations.Resistance = append(relations.Resistance, df)
+		}
 	}
 
-	for _, ty := range typeList {
-		ty.Thumbnail = "https://github.com/haynesherway/pogo/blob/master/pics/" + strings.ToLower(ty.Name) + ".png?raw=true"
-		//ty.Thumbnail = "https://github.com/PokeAPI/sprites/blob/master/sprites/items/"+strings.ToLower(ty.Name)+"-gem.png?raw=true"
-		typeMap[ty.ID] = ty
-		typeToID[strings.ToLower(ty.Name)] = ty.ID
+	for _, r := range []TypeRelation{relations.SuperEffective, relations.NotEffective, relations.Weakness, relations.Resistance} {
+		sort.SliceStable(r, func(i, j int) bool {
+			return math.Abs(float64(r[i].Tier)) > math.Abs(float64(r[j].Tier))
+		})
 	}
+	return relations
 }
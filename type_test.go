@@ -0,0 +1,59 @@
+package pogo
+
+import (
+	"testing"
+)
+
+func TestTypeRelations(t *testing.T) {
+	gyarados, err := GetPokemon("gyarados")
+	if err != nil {
+		t.Fatal("Unable to get gyarados:", err)
+	}
+	if w := gyarados.Weakness.Print(); w != "Electric(x2), Rock" {
+		t.Error("Expected Electric(x2), Rock, got", w)
+	}
+	if r := gyarados.Resistance.Print(); r != "Ground(x2), Fighting, Bug, Steel, Fire, Water" {
+		t.Error("Expected resistances in type order, got", r)
+	}
+	if e := gyarados.Weakness[0]; e.Type.ID != "POKEMON_TYPE_ELECTRIC" || e.Tier != TIER_DOUBLE_WEAK || e.Tier.String() != "double weak" {
+		t.Error("Expected double weak to electric, got", e)
+	}
+	if e := gyarados.Resistance[0]; e.Multiplier != 0.390625 || e.Tier != TIER_DOUBLE_RESIST {
+		t.Error("Expected double resist to ground, got", e)
+	}
+
+	// Every load gives the same order
+	for i := 0; i < 5; i++ {
+		p, _ := GetPokemon("gyarados")
+		if p.Resistance.Print() != gyarados.Resistance.Print() || p.SuperEffective.Print() != gyarados.SuperEffective.Print() {
+			t.Fatal("Expected a stable order, got", p.Resistance.Print(), p.SuperEffective.Print())
+		}
+	}
+
+	ghost, err := GetType("ghost")
+	if err != nil {
+		t.Fatal("Unable to get ghost:", err)
+	}
+	if r := ghost.Resistance.Print(); r != "Normal(x2), Fighting(x2), Poison, Bug" {
+		t.Error("Expected ghost resistances, got", r)
+	}
+	if s := ghost.SuperEffective.Print(); s != "Ghost, Psychic" {
+		t.Error("Expected ghost super effective, got", s)
+	}
+}
+
+func TestGetTier(t *testing.T) {
+	tests := map[float64]TypeTier{
+		0.244140625: TIER_TRIPLE_RESIST,
+		0.390625:    TIER_DOUBLE_RESIST,
+		0.625:       TIER_RESIST,
+		1:           TIER_NEUTRAL,
+		1.6:         TIER_WEAK,
+		2.56:        TIER_DOUBLE_WEAK,
+	}
+	for multiplier, tier := range tests {
+		if got := getTier(multiplier); got != tier {
+			t.Error("For", multiplier, "expected", tier, "got", got)
+		}
+	}
+}
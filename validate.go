@@ -0,0 +1,133 @@
+package pogo
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ProblemKind is the kind of problem found by Validate
+type ProblemKind string
+
+// Problems found by Validate
+const (
+	PROBLEM_NO_STATS          ProblemKind = "no base stats"
+	PROBLEM_UNKNOWN_TYPE      ProblemKind = "unknown type"
+	PROBLEM_UNKNOWN_MOVE      ProblemKind = "unknown move"
+	PROBLEM_DUPLICATE_MOVE    ProblemKind = "duplicate move name"
+	PROBLEM_DUPLICATE_ID      ProblemKind = "duplicate id"
+	PROBLEM_MAX_CP            ProblemKind = "max CP mismatch"
+	PROBLEM_DUPLICATE_FORM    ProblemKind = "duplicate form"
+	PROBLEM_UNKNOWN_EVOLUTION ProblemKind = "unknown evolution"
+)
+
+// Problem is a single issue found in the game data
+type Problem struct {
+	Kind    ProblemKind `json:"kind"`
+	ID      string      `json:"id"`
+	Message string      `json:"message"`
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %s: %s", p.ID, p.Kind, p.Message)
+}
+
+// Validate checks the game data for broken references and inconsistencies:
+// unknown types, moves and evolutions, duplicate pokemon and form ids, move
+// names that can't be looked up because another move has them, and max CP values that don't match GetCP at level 40 with perfect IVs. It
+// returns every problem found, or nil if the data is consistent.
+func (d *Pokedex) Validate() (problems []Problem) {
+	add := func(kind ProblemKind, id string, format string, a ...interface{}) {
+		problems = append(problems, Problem{Kind: kind, ID: id, Message: fmt.Sprintf(format, a...)})
+	}
+
+	// Types
+	for _, ty := range d.typeMap {
+		for _, damage := range ty.Damage {
+			if _, ok := d.typeMap[damage.ID]; !ok {
+				add(PROBLEM_UNKNOWN_TYPE, ty.ID, "damage against unknown type %s", damage.ID)
+			}
+		}
+	}
+
+	// Moves
+	for _, move := range d.moveMap {
+		if _, ok := d.typeMap[move.Type.ID]; !ok {
+			add(PROBLEM_UNKNOWN_TYPE, move.ID, "move has unknown type %q", move.Type.ID)
+		}
+		if id := d.moveToID[moveKey(move.Name)]; id != move.ID {
+			add(PROBLEM_DUPLICATE_MOVE, move.ID, "name %q finds %s instead", move.Name, id)
+		}
+	}
+
+	// Pokemon
+	pokemonIDs := map[string]bool{}
+	formOwners := map[string]Pokemon{}
+	for _, p := range d.pokemonList {
+		if pokemonIDs[p.ID] {
+			add(PROBLEM_DUPLICATE_ID, p.ID, "pokemon appears more than once")
+		}
+		pokemonIDs[p.ID] = true
+
+		if p.Stats.BaseAttack <= 0 || p.Stats.BaseDefense <= 0 || p.Stats.BaseStamina <= 0 {
+			add(PROBLEM_NO_STATS, p.ID, "base stats are %d/%d/%d", p.Stats.BaseAttack, p.Stats.BaseDefense, p.Stats.BaseStamina)
+		}
+
+		if len(p.Types) == 0 {
+			add(PROBLEM_UNKNOWN_TYPE, p.ID, "pokemon has no types")
+		}
+		for _, ty := range p.Types {
+			if _, ok := d.typeMap[ty.ID]; !ok {
+				add(PROBLEM_UNKNOWN_TYPE, p.ID, "pokemon has unknown type %q", ty.ID)
+			}
+		}
+
+		for _, m := range p.Moves.Fast {
+			if _, ok := d.moveMap[m.ID]; !ok {
+				add(PROBLEM_UNKNOWN_MOVE, p.ID, "unknown fast move %s", m.ID)
+			}
+		}
+		for _, m := range p.Moves.Charge {
+			if _, ok := d.moveMap[m.ID]; !ok {
+				add(PROBLEM_UNKNOWN_MOVE, p.ID, "unknown charge move %s", m.ID)
+			}
+		}
+
+		if cp := p.GetCP(40, 15, 15, 15); p.MaxCP != 0 && p.MaxCP != cp {
+			add(PROBLEM_MAX_CP, p.ID, "maxCP is %d but level 40 15/15/15 is %d", p.MaxCP, cp)
+		}
+
+		if b := p.Evolution.PastBranch; b != nil {
+			if _, ok := d.pokemonMap[pokemonKey(b.ID)]; !ok {
+				add(PROBLEM_UNKNOWN_EVOLUTION, p.ID, "evolves from unknown pokemon %s", b.ID)
+			}
+		}
+		for _, b := range p.Evolution.FutureBranches {
+			if _, ok := d.pokemonMap[pokemonKey(b.ID)]; !ok {
+				add(PROBLEM_UNKNOWN_EVOLUTION, p.ID, "evolves into unknown pokemon %s", b.ID)
+			}
+		}
+
+		formIDs := map[string]bool{}
+		for _, form := range p.Forms {
+			formID, _ := formName(form)
+			if formIDs[formID] {
+				add(PROBLEM_DUPLICATE_FORM, p.ID, "form %s is listed more than once", form.ID)
+			}
+			formIDs[formID] = true
+
+			if owner, ok := formOwners[formID]; ok && owner.Dex != p.Dex {
+				add(PROBLEM_DUPLICATE_FORM, p.ID, "form %s also belongs to %s", form.ID, owner.ID)
+			} else if !ok {
+				formOwners[formID] = p
+			}
+		}
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Kind == problems[j].Kind {
+			return problems[i].ID < problems[j].ID
+		}
+		return problems[i].Kind < problems[j].Kind
+	})
+	return problems
+}
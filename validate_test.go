@@ -0,0 +1,63 @@
+package pogo
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestValidateBundled(t *testing.T) {
+	d, err := Load(LoadOptions{})
+	if err != nil {
+		t.Fatal("Unable to load pokedex:", err)
+	}
+	for _, problem := range d.Validate() {
+		t.Error(problem)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	d, err := NewPokedex(fstest.MapFS{
+		"type.json": {Data: []byte(`[{"id":"POKEMON_TYPE_NORMAL","name":"Normal","damage":[{"id":"POKEMON_TYPE_NORMAL","attackScalar":1}]}]`)},
+		"move.json": {Data: []byte(`[
+			{"id":"TACKLE_FAST","name":"Tackle","pokemonType":{"id":"POKEMON_TYPE_NORMAL"}},
+			{"id":"TACKLE","name":"Tackle","pokemonType":{"id":"POKEMON_TYPE_NORMAL"}}]`)},
+		"pokemon.json": {Data: []byte(`[
+			{"id":"PIDGEY","dex":16,"maxCP":680,"types":[{"id":"POKEMON_TYPE_NORMAL"}],"stats":{"baseAttack":85,"baseDefense":73,"baseStamina":120},
+				"quickMoves":[{"id":"TACKLE_FAST"},{"id":"GUST_FAST"}],"forms":[{"id":"PIDGEY"},{"id":"PIDGEY"}]},
+			{"id":"RATTATA","dex":19,"maxCP":600,"types":[{"id":"POKEMON_TYPE_DARK"}],"stats":{"baseAttack":103,"baseDefense":70,"baseStamina":102},
+				"forms":[{"id":"PIDGEY"}]},
+			{"id":"RATTATA","dex":19,"types":[{"id":"POKEMON_TYPE_NORMAL"}],"stats":{"baseAttack":103,"baseDefense":70,"baseStamina":102}}]`)},
+	})
+	if err != nil {
+		t.Fatal("Unable to load pokedex:", err)
+	}
+
+	found := map[ProblemKind]int{}
+	for _, problem := range d.Validate() {
+		found[problem.Kind]++
+	}
+	expected := map[ProblemKind]int{
+		PROBLEM_UNKNOWN_MOVE:   1,
+		PROBLEM_DUPLICATE_MOVE: 1,
+		PROBLEM_UNKNOWN_TYPE:   1,
+		PROBLEM_DUPLICATE_ID:   1,
+		PROBLEM_MAX_CP:         1,
+		PROBLEM_DUPLICATE_FORM: 2,
+	}
+	// The fast and charge moves share a name, so only the ids find both
+	if m, err := d.GetMove("tackle"); err != nil || m.ID != "TACKLE" {
+		t.Error("Expected tackle to find the charge move, got", m, err)
+	}
+	if m, err := d.GetMove("TACKLE_FAST"); err != nil || m.ID != "TACKLE_FAST" {
+		t.Error("Expected the fast move by id, got", m, err)
+	}
+
+	for kind, count := range expected {
+		if found[kind] != count {
+			t.Error("For", kind, "expected", count, "problems, got", found[kind])
+		}
+	}
+	if len(found) != len(expected) {
+		t.Error("Expected", expected, "got", found)
+	}
+}
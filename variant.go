@@ -0,0 +1,129 @@
+package pogo
+
+import (
+	"strings"
+)
+
+// Variant marks a pokemon as a shadow or purified version of its species
+type Variant string
+
+// Variants
+const (
+	VARIANT_NORMAL   Variant = ""
+	VARIANT_SHADOW   Variant = "shadow"
+	VARIANT_PURIFIED Variant = "purified"
+)
+
+// Shadow pokemon deal more damage in battle, but also take more
+const (
+	SHADOW_ATTACK_BONUS  = 1.2
+	SHADOW_DEFENSE_BONUS = 5.0 / 6.0
+)
+
+// Purifying a shadow pokemon raises each IV and brings it up to at least
+// PURIFIED_LEVEL
+const (
+	PURIFIED_IV_BONUS = 2
+	PURIFIED_LEVEL    = 25.0
+)
+
+// variantFromID returns the variant of a pokemon or form id such as MACHOP_SHADOW
+func variantFromID(id string) Variant {
+	id = strings.ToUpper(strings.Replace(id, "-", "_", -1))
+	switch {
+	case strings.HasSuffix(id, "_SHADOW"):
+		return VARIANT_SHADOW
+	case strings.HasSuffix(id, "_PURIFIED"):
+		return VARIANT_PURIFIED
+	}
+	return VARIANT_NORMAL
+}
+
+// AttackModifier is the multiplier applied to attack in damage calculations
+func (v Variant) AttackModifier() float64 {
+	if v == VARIANT_SHADOW {
+		return SHADOW_ATTACK_BONUS
+	}
+	return 1
+}
+
+// DefenseModifier is the multiplier applied to defense in damage calculations
+func (v Variant) DefenseModifier() float64 {
+	if v == VARIANT_SHADOW {
+		return SHADOW_DEFENSE_BONUS
+	}
+	return 1
+}
+
+// powerUpCost adjusts a stardust, candy or XL candy cost for the variant: shadow
+// pokemon cost 20% more to power up and purified pokemon 10% less, rounded up
+func (v Variant) powerUpCost(cost int) int {
+	switch v {
+	case VARIANT_SHADOW:
+		return (cost*12 + 9) / 10
+	case VARIANT_PURIFIED:
+		return (cost*9 + 9) / 10
+	}
+	return cost
+}
+
+// IsShadow returns true if the pokemon is a shadow pokemon
+func (p *Pokemon) IsShadow() bool {
+	return p.Variant == VARIANT_SHADOW
+}
+
+// IsPurified returns true if the pokemon is a purified pokemon
+func (p *Pokemon) IsPurified() bool {
+	return p.Variant == VARIANT_PURIFIED
+}
+
+// GetShadow returns the shadow version of the pokemon
+func (p *Pokemon) GetShadow() (*Pokemon, error) {
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	return d.GetPokemon(p.baseID() + "-shadow")
+}
+
+// GetPurified returns the purified version of the pokemon
+func (p *Pokemon) GetPurified() (*Pokemon, error) {
+	d, err := p.getPokedex()
+	if err != nil {
+		return nil, err
+	}
+	return d.GetPokemon(p.baseID() + "-purified")
+}
+
+// baseID returns the id of the pokemon without its shadow or purified suffix
+func (p *Pokemon) baseID() string {
+	id := strings.ToLower(p.ID)
+	id = strings.TrimSuffix(id, "-shadow")
+	return strings.TrimSuffix(id, "-purified")
+}
+
+// PurifyIV returns the level and IVs of a shadow pokemon after it is purified
+func PurifyIV(level float64, ivAttack int, ivDefense int, ivStamina int) (float64, int, int, int) {
+	purify := func(iv int) int {
+		iv += PURIFIED_IV_BONUS
+		if iv > 15 {
+			iv = 15
+		}
+		return iv
+	}
+	if level < PURIFIED_LEVEL {
+		level = PURIFIED_LEVEL
+	}
+	return level, purify(ivAttack), purify(ivDefense), purify(ivStamina)
+}
+
+// GetPowerUpCost returns what it takes to power up the pokemon once from a
+// level, including the shadow or purified modifier
+func (p *Pokemon) GetPowerUpCost(level float64) PowerUpCost {
+	cost := powerUpCost(level)
+	return PowerUpCost{
+		Stardust: p.Variant.powerUpCost(cost.Stardust),
+		Candy:    p.Variant.powerUpCost(cost.Candy),
+		XLCandy:  p.Variant.powerUpCost(cost.XLCandy),
+	}
+}
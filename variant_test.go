@@ -0,0 +1,88 @@
+package pogo
+
+import (
+	"testing"
+)
+
+func TestShadowPokemon(t *testing.T) {
+	shadow, err := GetPokemon("bulbasaur-shadow")
+	if err != nil {
+		t.Fatal("Unable to get shadow pokemon:", err)
+	}
+	if !shadow.IsShadow() || shadow.Name != "Bulbasaur Shadow" {
+		t.Error("Expected Bulbasaur Shadow, got", shadow.Name, shadow.Variant)
+	}
+
+	normal, err := GetPokemon("1")
+	if err != nil || normal.ID != "bulbasaur" || normal.Variant != VARIANT_NORMAL {
+		t.Fatal("Expected bulbasaur for dex 1, got", normal, err)
+	}
+	if shadow.GetCP(20, 15, 15, 15) != normal.GetCP(20, 15, 15, 15) {
+		t.Error("Expected shadow CP to match normal CP")
+	}
+	if attack := shadow.GetAttack(20, 15); attack != normal.GetAttack(20, 15)*SHADOW_ATTACK_BONUS {
+		t.Error("Expected shadow attack bonus, got", attack)
+	}
+	if defense := shadow.GetDefense(20, 15); defense != normal.GetDefense(20, 15)*SHADOW_DEFENSE_BONUS {
+		t.Error("Expected shadow defense penalty, got", defense)
+	}
+
+	purified, err := shadow.GetPurified()
+	if err != nil || !purified.IsPurified() {
+		t.Fatal("Expected purified bulbasaur, got", purified, err)
+	}
+	if s, err := purified.GetShadow(); err != nil || s.ID != shadow.ID {
+		t.Error("Expected shadow bulbasaur, got", s, err)
+	}
+}
+
+func TestPurifyIV(t *testing.T) {
+	tests := []struct {
+		level         float64
+		a, d, s       int
+		purifiedLevel float64
+		pa, pd, ps    int
+	}{
+		{8, 0, 7, 14, 25, 2, 9, 15},
+		{30, 15, 13, 10, 30, 15, 15, 12},
+	}
+	for _, test := range tests {
+		level, a, d, s := PurifyIV(test.level, test.a, test.d, test.s)
+		if level != test.purifiedLevel || a != test.pa || d != test.pd || s != test.ps {
+			t.Error("For", test.level, test.a, test.d, test.s, "got", level, a, d, s)
+		}
+	}
+}
+
+func TestGetPowerUpCost(t *testing.T) {
+	tests := []struct {
+		variant         Variant
+		level           float64
+		stardust, candy int
+	}{
+		{VARIANT_NORMAL, 1, 200, 1},
+		{VARIANT_SHADOW, 1, 240, 2},
+		{VARIANT_PURIFIED, 1, 180, 1},
+		{VARIANT_NORMAL, 35, 8000, 10},
+		{VARIANT_SHADOW, 35, 9600, 12},
+		{VARIANT_PURIFIED, 35, 7200, 9},
+	}
+	for _, test := range tests {
+		p := &Pokemon{Variant: test.variant}
+		cost := p.GetPowerUpCost(test.level)
+		if cost.Stardust != test.stardust || cost.Candy != test.candy {
+			t.Error("For", test.variant, test.level, "expected", test.stardust, test.candy, "got", cost)
+		}
+	}
+}
+
+func TestGetDamage(t *testing.T) {
+	move := &Move{Power: 10, Type: PokemonType{ID: "POKEMON_TYPE_FIGHTING"}}
+	attacker := &Pokemon{Types: TypeList{{ID: "POKEMON_TYPE_FIGHTING"}}}
+	defender := &Pokemon{Types: TypeList{{ID: "POKEMON_TYPE_NORMAL"}}}
+
+	// 0.5 * 10 power * 200/100 * 1.2 STAB * 1.6 super effective, plus one
+	if damage := GetDamage(move, attacker, 200, defender, 100); damage != 20 {
+		t.Error("Expected 20 damage, got", damage)
+	}
+}